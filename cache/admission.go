@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAdmissionCounters is the default number of counters of an
+	// admission filter, each using one byte of memory
+	DefaultAdmissionCounters = 1 << 18
+
+	// admissionDepth is the number of rows of the count-min sketch of an
+	// admission filter, each key being counted once per row
+	admissionDepth = 4
+)
+
+// AdmissionOptions defines when a value found in a cache of a chain is set
+// back in the previous caches: a value is only set back once its key has been
+// found in a next cache a given number of times within a time window, so that
+// keys read once (by a scan for instance) don't evict the frequently read ones
+// from the first caches.
+//
+// Keys are counted using a count-min sketch of a fixed number of counters:
+// different keys may share a counter, which may admit a key seen less often
+// than required, but never rejects a key seen often enough.
+type AdmissionOptions struct {
+	// MinHits is the number of times a key has to be found in a next cache to
+	// be set back, up to 255
+	MinHits int
+	// Window is the time during which keys are counted, all the counters being
+	// reset afterwards. Keys are counted indefinitely when zero.
+	Window time.Duration
+	// Counters is the number of counters used to count the keys, each using
+	// one byte of memory, DefaultAdmissionCounters when zero. It should be a
+	// few times larger than the number of keys read within the window.
+	Counters int
+}
+
+func (o AdmissionOptions) minHits() uint8 {
+	if o.MinHits > math.MaxUint8 {
+		return math.MaxUint8
+	}
+
+	return uint8(o.MinHits)
+}
+
+// width returns the number of counters of each row of the sketch, as a power
+// of two
+func (o AdmissionOptions) width() uint64 {
+	counters := o.Counters
+	if counters <= 0 {
+		counters = DefaultAdmissionCounters
+	}
+
+	width := uint64(1)
+	for width*2*admissionDepth <= uint64(counters) {
+		width *= 2
+	}
+
+	return width
+}
+
+// admissionFilter counts the keys found in the next caches of a chain using a
+// count-min sketch, admitting them once seen often enough
+type admissionFilter struct {
+	options AdmissionOptions
+	minHits uint8
+	mask    uint64
+	seed    maphash.Seed
+
+	mtx      sync.Mutex
+	counters []uint8
+	resetAt  time.Time
+}
+
+func newAdmissionFilter(options AdmissionOptions) *admissionFilter {
+	width := options.width()
+
+	f := &admissionFilter{
+		options:  options,
+		minHits:  options.minHits(),
+		mask:     width - 1,
+		seed:     maphash.MakeSeed(),
+		counters: make([]uint8, width*admissionDepth),
+	}
+	if options.Window > 0 {
+		f.resetAt = time.Now().Add(options.Window)
+	}
+
+	return f
+}
+
+// admit counts the given key and returns whether it has been seen often enough
+// to be set back in the previous caches
+func (f *admissionFilter) admit(key string) bool {
+	hash := maphash.String(f.seed, key)
+	// Indexes of each row are derived from two halves of the hash
+	h1, h2 := hash&math.MaxUint32, hash>>32|1
+
+	var indexes [admissionDepth]uint64
+	for row := range indexes {
+		indexes[row] = uint64(row)*(f.mask+1) + (h1+uint64(row)*h2)&f.mask
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !f.resetAt.IsZero() && time.Now().After(f.resetAt) {
+		for i := range f.counters {
+			f.counters[i] = 0
+		}
+		f.resetAt = time.Now().Add(f.options.Window)
+	}
+
+	count := uint8(math.MaxUint8)
+	for _, index := range indexes {
+		if f.counters[index] < count {
+			count = f.counters[index]
+		}
+	}
+
+	if count < math.MaxUint8 {
+		count++
+		// Conservative update: only the smallest counters are incremented,
+		// limiting the overestimation of keys sharing counters
+		for _, index := range indexes {
+			if f.counters[index] < count {
+				f.counters[index] = count
+			}
+		}
+	}
+
+	return count >= f.minHits
+}
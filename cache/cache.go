@@ -18,19 +18,26 @@ const (
 
 // Cache represents the configuration needed by a cache
 type Cache[T any] struct {
-	Codec codec.CodecInterface
+	Codec   codec.CodecInterface
+	Options *CacheOptions
+
+	generations namespaceGenerations
 }
 
 // New instantiates a new cache entry
-func New[T any](store store.StoreInterface) *Cache[T] {
+func New[T any](store store.StoreInterface, options ...CacheOption) *Cache[T] {
 	return &Cache[T]{
-		Codec: codec.New(store),
+		Codec:   codec.New(store),
+		Options: applyCacheOptions(options...),
 	}
 }
 
 // Get returns the object stored in cache if it exists
 func (c *Cache[T]) Get(ctx context.Context, key any) (T, error) {
-	cacheKey := c.GetCacheKey(key)
+	cacheKey, err := c.getCacheKey(ctx, key)
+	if err != nil {
+		return *new(T), err
+	}
 
 	value, err := c.Codec.Get(ctx, cacheKey)
 	if err != nil {
@@ -46,7 +53,10 @@ func (c *Cache[T]) Get(ctx context.Context, key any) (T, error) {
 
 // GetWithTTL returns the object stored in cache and its corresponding TTL
 func (c *Cache[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
-	cacheKey := c.GetCacheKey(key)
+	cacheKey, err := c.getCacheKey(ctx, key)
+	if err != nil {
+		return *new(T), 0, err
+	}
 
 	value, duration, err := c.Codec.GetWithTTL(ctx, cacheKey)
 	if err != nil {
@@ -60,18 +70,93 @@ func (c *Cache[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, e
 	return *new(T), duration, nil
 }
 
+// GetTags returns the tags the object stored for the given key has been set
+// with, when the store keeps them (see store.TagsGetter)
+func (c *Cache[T]) GetTags(ctx context.Context, key any) ([]string, error) {
+	cacheKey, err := c.getCacheKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.GetTags(ctx, c.Codec.GetStore(), cacheKey)
+}
+
+// GetMany returns the objects stored in cache for the given keys, indexed by
+// key. Keys that are not found are missing from the returned map.
+func (c *Cache[T]) GetMany(ctx context.Context, keys []any) (map[any]T, error) {
+	keysByCacheKey := make(map[string][]any, len(keys))
+	cacheKeys := make([]any, 0, len(keys))
+
+	for _, key := range keys {
+		cacheKey, err := c.getCacheKey(ctx, key)
+		if err != nil {
+			return map[any]T{}, err
+		}
+		if _, ok := keysByCacheKey[cacheKey]; !ok {
+			cacheKeys = append(cacheKeys, cacheKey)
+		}
+		keysByCacheKey[cacheKey] = append(keysByCacheKey[cacheKey], key)
+	}
+
+	values, err := c.Codec.GetMany(ctx, cacheKeys)
+
+	objects := make(map[any]T, len(values))
+	for cacheKey, value := range values {
+		object, _ := value.(T)
+		for _, key := range keysByCacheKey[cacheKey.(string)] {
+			objects[key] = object
+		}
+	}
+
+	return objects, err
+}
+
 // Set populates the cache item using the given key
 func (c *Cache[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
-	cacheKey := c.GetCacheKey(key)
+	cacheKey, err := c.getCacheKey(ctx, key)
+	if err != nil {
+		return err
+	}
 	return c.Codec.Set(ctx, cacheKey, object, options...)
 }
 
+// SetMany populates the cache items using the given keys
+func (c *Cache[T]) SetMany(ctx context.Context, items map[any]T, options ...store.Option) error {
+	values := make(map[any]any, len(items))
+	for key, object := range items {
+		cacheKey, err := c.getCacheKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		values[cacheKey] = object
+	}
+
+	return c.Codec.SetMany(ctx, values, options...)
+}
+
 // Delete removes the cache item using the given key
 func (c *Cache[T]) Delete(ctx context.Context, key any) error {
-	cacheKey := c.GetCacheKey(key)
+	cacheKey, err := c.getCacheKey(ctx, key)
+	if err != nil {
+		return err
+	}
 	return c.Codec.Delete(ctx, cacheKey)
 }
 
+// DeleteMany removes the cache items using the given keys
+func (c *Cache[T]) DeleteMany(ctx context.Context, keys []any) error {
+	cacheKeys := make([]any, 0, len(keys))
+	for _, key := range keys {
+		cacheKey, err := c.getCacheKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		cacheKeys = append(cacheKeys, cacheKey)
+	}
+
+	return c.Codec.DeleteMany(ctx, cacheKeys)
+}
+
 // Invalidate invalidates cache item from given options
 func (c *Cache[T]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
 	return c.Codec.Invalidate(ctx, options...)
@@ -82,11 +167,37 @@ func (c *Cache[T]) Clear(ctx context.Context) error {
 	return c.Codec.Clear(ctx)
 }
 
+// InvalidateNamespace invalidates all the values of the given versioned
+// namespace at once, by writing a new generation of the namespace in the
+// store: values of the previous generations are not reachable anymore and
+// expire naturally. Other instances sharing the store stop returning them
+// once their local generation has expired.
+func (c *Cache[T]) InvalidateNamespace(ctx context.Context, namespace string) error {
+	_, err := c.generations.bump(ctx, c.Codec.GetStore(), namespace, c.namespaceGenerationTTL())
+	return err
+}
+
 // GetCodec returns the current codec
 func (c *Cache[T]) GetCodec() codec.CodecInterface {
 	return c.Codec
 }
 
+// CodecOf returns the codec of the given cache, looked up through the metric
+// and loadable caches wrapping it, or nil when it has none (a chain cache for
+// instance)
+func CodecOf[T any](cache CacheInterface[T]) codec.CodecInterface {
+	switch current := cache.(type) {
+	case interface{ GetCodec() codec.CodecInterface }:
+		return current.GetCodec()
+	case *MetricCache[T]:
+		return CodecOf(current.Cache)
+	case *LoadableCache[T]:
+		return CodecOf(current.Cache)
+	}
+
+	return nil
+}
+
 // GetType returns the cache type
 func (c *Cache[T]) GetType() string {
 	return CacheType
@@ -94,8 +205,59 @@ func (c *Cache[T]) GetType() string {
 
 // GetCacheKey returns the cache key for the given key object by returning
 // the key if type is string or by computing a Checksum of key structure
-// if its type is other than string
+// if its type is other than string. Keys belonging to a versioned namespace
+// are prefixed with the namespace, but not with its generation as it is only
+// known once read from the store.
 func (c *Cache[T]) GetCacheKey(key any) string {
+	namespace, cacheKey := c.splitCacheKey(key)
+	if namespace == "" {
+		return cacheKey
+	}
+
+	return namespace + ":" + cacheKey
+}
+
+// getCacheKey returns the key under which the given key object is stored:
+// its cache key, prefixed with its namespace and the current generation of
+// the namespace when it belongs to a versioned namespace
+func (c *Cache[T]) getCacheKey(ctx context.Context, key any) (string, error) {
+	namespace, cacheKey := c.splitCacheKey(key)
+	if namespace == "" {
+		return cacheKey, nil
+	}
+
+	generation, err := c.generations.get(ctx, c.Codec.GetStore(), namespace, c.namespaceGenerationTTL())
+	if err != nil {
+		return "", err
+	}
+
+	return namespace + ":" + generation + ":" + cacheKey, nil
+}
+
+// splitCacheKey returns the versioned namespace of the given key object (if
+// any) and its cache key within the namespace
+func (c *Cache[T]) splitCacheKey(key any) (string, string) {
+	switch v := key.(type) {
+	case NamespacedKey:
+		return v.Namespace, getCacheKey(v.Key)
+	case CacheNamespaceProvider:
+		return v.GetCacheNamespace(), getCacheKey(key)
+	}
+
+	if c.Options != nil {
+		return c.Options.Namespace, getCacheKey(key)
+	}
+	return "", getCacheKey(key)
+}
+
+func (c *Cache[T]) namespaceGenerationTTL() time.Duration {
+	if c.Options != nil {
+		return c.Options.NamespaceGenerationTTL
+	}
+	return DefaultNamespaceGenerationTTL
+}
+
+func getCacheKey(key any) string {
 	switch v := key.(type) {
 	case string:
 		return v
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"time"
+)
+
+// DefaultNamespaceGenerationTTL is the default duration during which the
+// generation of a namespace is kept locally before being read again
+const DefaultNamespaceGenerationTTL = time.Second
+
+// CacheOption represents a cache option function.
+type CacheOption func(o *CacheOptions)
+
+type CacheOptions struct {
+	Namespace              string
+	NamespaceGenerationTTL time.Duration
+}
+
+func applyCacheOptions(opts ...CacheOption) *CacheOptions {
+	o := &CacheOptions{
+		NamespaceGenerationTTL: DefaultNamespaceGenerationTTL,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithNamespace allows to specify the versioned namespace of the keys that do
+// not belong to a namespace (see NamespacedKey). All the values of a namespace
+// can be invalidated at once using InvalidateNamespace, or when the store
+// evicts the generation of the namespace (see InNamespace).
+func WithNamespace(namespace string) CacheOption {
+	return func(o *CacheOptions) {
+		o.Namespace = namespace
+	}
+}
+
+// WithNamespaceGenerationTTL allows to specify the duration during which the
+// generation of a namespace is kept locally before being read again from the
+// store. Values invalidated by another instance may still be returned during
+// this duration. Defaults to one second.
+func WithNamespaceGenerationTTL(ttl time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.NamespaceGenerationTTL = ttl
+	}
+}
@@ -382,3 +382,85 @@ func TestCacheDeleteWhenError(t *testing.T) {
 	// Then
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestCacheGetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	key := struct {
+		Hello string
+	}{
+		Hello: "world",
+	}
+	cacheKey := cache.Checksum(key)
+
+	mockedStore := NewMockStoreInterface(ctrl)
+	mockedStore.EXPECT().GetMany(ctx, []any{"my-key", cacheKey, "my-missing-key"}).Return(map[any]any{
+		"my-key": "my-value",
+		cacheKey: "my-struct-value",
+	}, nil)
+
+	ch := cache.New[string](mockedStore)
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"my-key", key, "my-missing-key", "my-key"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]string{
+		"my-key": "my-value",
+		key:      "my-struct-value",
+	}, values)
+}
+
+func TestCacheSetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	key := struct {
+		Hello string
+	}{
+		Hello: "world",
+	}
+
+	mockedStore := NewMockStoreInterface(ctrl)
+	mockedStore.EXPECT().SetMany(ctx, map[any]any{
+		"my-key":            "my-value",
+		cache.Checksum(key): "my-struct-value",
+	}, store.OptionsMatcher{
+		Expiration: 5 * time.Second,
+	}).Return(nil)
+
+	ch := cache.New[string](mockedStore)
+
+	// When
+	err := ch.SetMany(ctx, map[any]string{
+		"my-key": "my-value",
+		key:      "my-struct-value",
+	}, store.WithExpiration(5*time.Second))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestCacheDeleteMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	mockedStore := NewMockStoreInterface(ctrl)
+	mockedStore.EXPECT().DeleteMany(ctx, []any{"key-1", "key-2"}).Return(nil)
+
+	ch := cache.New[any](mockedStore)
+
+	// When
+	err := ch.DeleteMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/prodadidb/gocache/store"
@@ -14,82 +15,444 @@ const (
 	ChainType = "chain"
 )
 
+// TagsGetter is implemented by caches able to return the tags an object has
+// been set with
+type TagsGetter interface {
+	GetTags(ctx context.Context, key any) ([]string, error)
+}
+
+// ErrChainClosed is returned when writing in the next caches of a closed chain
+// using the WriteBehind policy
+var ErrChainClosed = errors.New("chain cache is closed")
+
 type chainKeyValue[T any] struct {
-	key       any
-	value     T
-	ttl       time.Duration
-	storeType *string
+	key   any
+	value T
+	ttl   time.Duration
+	// layer is the position in the chain of the cache the value was found in
+	layer int
+}
+
+// ChainStats allows to returns some statistics of chain cache usage
+type ChainStats struct {
+	// LayerHits is the number of values found in each cache of the chain,
+	// indexed by their position in the chain
+	LayerHits []int
+	// Misses is the number of values found in none of the caches
+	Misses int
+	// HedgedReads is the number of caches requested before the previous one
+	// answered (see WithHedgedReads)
+	HedgedReads int
+	// BackfillsRejected is the number of values not set back in the previous
+	// caches by the admission filter (see WithBackfillAdmission)
+	BackfillsRejected int
 }
 
 // ChainCache represents the configuration needed by a cache aggregator
 type ChainCache[T any] struct {
-	Caches     []SetterCacheInterface[T]
+	Caches []SetterCacheInterface[T]
+	// SetChannel queues the values set back in the previous caches of the
+	// chain, nil when they are set synchronously
 	SetChannel chan *chainKeyValue[T]
+	Options    *ChainOptions
+
+	queue     *queue[*chainKeyValue[T]]
+	writes    *queue[func()]
+	admission *admissionFilter
+	stats     *ChainStats
+	statsMtx  sync.Mutex
 }
 
 // NewChain instantiates a new cache aggregator
 func NewChain[T any](caches ...SetterCacheInterface[T]) *ChainCache[T] {
+	return NewChainWithOptions(caches)
+}
+
+// NewChainWithOptions instantiates a new cache aggregator using the given
+// options
+func NewChainWithOptions[T any](caches []SetterCacheInterface[T], options ...ChainOption) *ChainCache[T] {
 	chain := &ChainCache[T]{
-		Caches:     caches,
-		SetChannel: make(chan *chainKeyValue[T], 10000),
+		Caches:  caches,
+		Options: applyChainOptions(options...),
+		stats:   &ChainStats{LayerHits: make([]int, len(caches))},
+	}
+
+	chain.queue = newQueue(chain.Options.Queue, func(item *chainKeyValue[T]) {
+		chain.backfill(context.Background(), item)
+	})
+	chain.SetChannel = chain.queue.items
+
+	if chain.Options.Admission != nil {
+		chain.admission = newAdmissionFilter(*chain.Options.Admission)
 	}
 
-	go chain.setter()
+	if chain.Options.WritePolicy == WriteBehind {
+		// Writes are never dropped, unlike the values set back
+		queueOptions := chain.Options.WriteBehind.Queue
+		queueOptions.DropOnFull = false
+
+		chain.writes = newQueue(queueOptions, func(write func()) {
+			write()
+		})
+	}
 
 	return chain
 }
 
-// setter sets a value in available caches, until a given cache layer
-func (c *ChainCache[T]) setter() {
-	for item := range c.SetChannel {
-		for _, cache := range c.Caches {
-			if item.storeType != nil && *item.storeType == cache.GetCodec().GetStore().GetType() {
-				break
-			}
+// Close stops setting values back in the previous caches of the chain (and in
+// its next caches using the WriteBehind policy), once the values already queued
+// have been set. Values found afterwards are not set back anymore.
+func (c *ChainCache[T]) Close() error {
+	c.queue.close()
+	if c.writes != nil {
+		c.writes.close()
+	}
 
-			_ = cache.Set(context.Background(), item.key, item.value, store.WithExpiration(item.ttl))
+	return nil
+}
+
+// Dropped returns the number of values which have not been set back in the
+// previous caches of the chain, as the queue was full (see
+// QueueOptions.DropOnFull) or the chain closed
+func (c *ChainCache[T]) Dropped() uint64 {
+	return c.queue.dropped.Load()
+}
+
+// backfillLater queues the given value to be set back in the caches preceding
+// the one it was found in, once admitted by the admission filter if any
+func (c *ChainCache[T]) backfillLater(item *chainKeyValue[T]) {
+	if c.admission != nil && !c.admission.admit(getCacheKey(item.key)) {
+		c.recordBackfillRejected()
+		return
+	}
+
+	c.queue.push(item)
+}
+
+// backfill sets the given value back in the caches preceding the one it was
+// found in, according to their backfill policy
+func (c *ChainCache[T]) backfill(ctx context.Context, item *chainKeyValue[T]) {
+	var tags []string
+	var tagsErr error
+	tagsRead := false
+
+	for layer, cache := range c.Caches[:item.layer] {
+		policy := c.Options.backfillPolicy(layer)
+
+		// Values whose time to live is unknown (read using GetMany, or from a
+		// store reporting none) are set using the default expiration of the
+		// cache instead of never expiring
+		var options []store.Option
+		if ttl := policy.ttl(item.ttl); ttl > 0 {
+			options = append(options, store.WithExpiration(ttl))
 		}
+		if policy.PropagateTags {
+			if !tagsRead {
+				tags, tagsErr = c.tags(ctx, item)
+				tagsRead = true
+			}
+			// Values are not set back without their tags, which would keep
+			// them from being invalidated by tag
+			if tagsErr != nil {
+				policy.onError(layerError(c.Caches[item.layer], "read tags from", tagsErr))
+				continue
+			}
+			if len(tags) > 0 {
+				options = append(options, store.WithTags(tags))
+			}
+		}
+
+		policy.onError(layerError(cache, "set", cache.Set(ctx, item.key, item.value, options...)))
 	}
 }
 
+// tags returns the tags of the given value in the cache it was found in, or
+// store.ErrTagsNotSupported when its store doesn't keep them
+func (c *ChainCache[T]) tags(ctx context.Context, item *chainKeyValue[T]) ([]string, error) {
+	getter, ok := c.Caches[item.layer].(TagsGetter)
+	if !ok {
+		return nil, store.ErrTagsNotSupported
+	}
+
+	return getter.GetTags(ctx, item.key)
+}
+
 // Get returns the object stored in cache if it exists
 func (c *ChainCache[T]) Get(ctx context.Context, key any) (T, error) {
 	var object T
 	var err error
 	var ttl time.Duration
+	var layer int
 
-	for _, cache := range c.Caches {
-		storeType := cache.GetCodec().GetStore().GetType()
-		object, ttl, err = cache.GetWithTTL(ctx, key)
-		if err == nil {
-			// Set the value back until this cache layer
-			c.SetChannel <- &chainKeyValue[T]{key, object, ttl, &storeType}
-			return object, nil
+	if c.Options.HedgeDelay > 0 && len(c.Caches) > 0 {
+		object, ttl, layer, err = c.hedgedGet(ctx, key)
+	} else {
+		for layer = range c.Caches {
+			object, ttl, err = c.Caches[layer].GetWithTTL(ctx, key)
+			if err == nil {
+				break
+			}
 		}
 	}
 
-	return object, err
+	if err != nil || len(c.Caches) == 0 {
+		c.recordMisses(1)
+		return object, err
+	}
+
+	c.recordHits(layer, 1)
+
+	if layer > 0 {
+		// Set the value back in the previous cache layers
+		c.backfillLater(&chainKeyValue[T]{key: key, value: object, ttl: ttl, layer: layer})
+	}
+
+	return object, nil
 }
 
-// Set sets a value in available caches
-func (c *ChainCache[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
+// chainGetResult is the answer of a cache of a chain to a hedged read
+type chainGetResult[T any] struct {
+	object T
+	ttl    time.Duration
+	layer  int
+	err    error
+}
+
+// hedgedGet requests the caches of the chain for the given key, requesting
+// the next cache when a cache hasn't answered within the hedge delay or hasn't
+// found the key. It returns the first value found along with the position of
+// the cache it was found in, or the error of the last cache.
+func (c *ChainCache[T]) hedgedGet(ctx context.Context, key any) (T, time.Duration, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan chainGetResult[T], len(c.Caches))
+	errs := make([]error, len(c.Caches))
+
+	next, pending := 0, 0
+	var hedge *time.Timer
+
+	request := func() {
+		go func(layer int, cache SetterCacheInterface[T]) {
+			object, ttl, err := cache.GetWithTTL(ctx, key)
+			results <- chainGetResult[T]{object: object, ttl: ttl, layer: layer, err: err}
+		}(next, c.Caches[next])
+		next++
+		pending++
+
+		if hedge != nil {
+			hedge.Stop()
+		}
+		hedge = time.NewTimer(c.Options.HedgeDelay)
+	}
+	defer func() {
+		if hedge != nil {
+			hedge.Stop()
+		}
+	}()
+
+	for next < len(c.Caches) || pending > 0 {
+		if pending == 0 {
+			if err := ctx.Err(); err != nil {
+				return *new(T), 0, 0, err
+			}
+			request()
+		}
+
+		select {
+		case <-ctx.Done():
+			return *new(T), 0, 0, ctx.Err()
+
+		case <-hedge.C:
+			if next < len(c.Caches) {
+				c.recordHedgedRead()
+				request()
+			}
+
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.object, result.ttl, result.layer, nil
+			}
+			errs[result.layer] = result.err
+		}
+	}
+
+	return *new(T), 0, 0, errs[len(errs)-1]
+}
+
+// GetMany returns the objects stored in caches for the given keys, indexed by
+// key. Each cache layer is only requested for the keys missing from the
+// previous ones, and the objects found are set back in the previous layers.
+func (c *ChainCache[T]) GetMany(ctx context.Context, keys []any) (map[any]T, error) {
+	objects := make(map[any]T, len(keys))
+	missingKeys := keys
 	errs := []error{}
-	for _, cache := range c.Caches {
-		err := cache.Set(ctx, key, object, options...)
+
+	for layer, cache := range c.Caches {
+		if len(missingKeys) == 0 {
+			break
+		}
+
+		values, err := cache.GetMany(ctx, missingKeys)
 		if err != nil {
-			storeType := cache.GetCodec().GetStore().GetType()
-			errs = append(errs, fmt.Errorf("Unable to set item into cache with store '%s': %v", storeType, err))
+			errs = append(errs, err)
 		}
+
+		remainingKeys := make([]any, 0, len(missingKeys))
+		for _, key := range missingKeys {
+			object, ok := values[key]
+			if !ok {
+				remainingKeys = append(remainingKeys, key)
+				continue
+			}
+
+			objects[key] = object
+
+			if layer > 0 {
+				// Set the value back in the previous cache layers
+				c.backfillLater(&chainKeyValue[T]{key: key, value: object, layer: layer})
+			}
+		}
+		c.recordHits(layer, len(missingKeys)-len(remainingKeys))
+		missingKeys = remainingKeys
 	}
-	if len(errs) > 0 {
-		errStr := ""
-		for k, v := range errs {
-			errStr += fmt.Sprintf("error %d of %d: %v", k+1, len(errs), v.Error())
+
+	if len(missingKeys) > 0 {
+		c.recordMisses(len(missingKeys))
+		return objects, errors.Join(errs...)
+	}
+
+	return objects, nil
+}
+
+// Set sets a value in the caches of the chain, according to its write policy
+func (c *ChainCache[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
+	return c.write(ctx, "item", func(ctx context.Context, cache SetterCacheInterface[T]) error {
+		return cache.Set(ctx, key, object, options...)
+	}, func(ctx context.Context, cache SetterCacheInterface[T]) error {
+		return cache.Delete(ctx, key)
+	})
+}
+
+// SetMany sets values in the caches of the chain, according to its write
+// policy
+func (c *ChainCache[T]) SetMany(ctx context.Context, items map[any]T, options ...store.Option) error {
+	return c.write(ctx, "items", func(ctx context.Context, cache SetterCacheInterface[T]) error {
+		return cache.SetMany(ctx, items, options...)
+	}, func(ctx context.Context, cache SetterCacheInterface[T]) error {
+		keys := make([]any, 0, len(items))
+		for key := range items {
+			keys = append(keys, key)
 		}
-		return errors.New(errStr)
+		return cache.DeleteMany(ctx, keys)
+	})
+}
+
+// write sets values in the caches of the chain using the given set function,
+// according to the write policy. The given delete function removes them from
+// the caches not written using the WriteAround policy. Errors of the caches
+// are joined, the written object being named as given.
+func (c *ChainCache[T]) write(ctx context.Context, object string, set, del layerWrite[T]) error {
+	action := "set " + object + " into"
+
+	if len(c.Caches) == 0 {
+		return nil
 	}
 
-	return nil
+	switch c.Options.WritePolicy {
+	case WriteThroughParallel:
+		errs := make([]error, len(c.Caches))
+		wg := &sync.WaitGroup{}
+		for i, cache := range c.Caches {
+			wg.Add(1)
+			go func(i int, cache SetterCacheInterface[T]) {
+				defer wg.Done()
+				errs[i] = layerError(cache, action, set(ctx, cache))
+			}(i, cache)
+		}
+		wg.Wait()
+
+		return errors.Join(errs...)
+
+	case WriteAround:
+		last := len(c.Caches) - 1
+		if err := set(ctx, c.Caches[last]); err != nil {
+			return layerError(c.Caches[last], action, err)
+		}
+
+		errs := []error{}
+		for _, cache := range c.Caches[:last] {
+			if err := del(ctx, cache); err != nil && !errors.Is(err, store.NotFound{}) {
+				errs = append(errs, layerError(cache, "delete "+object+" from", err))
+			}
+		}
+
+		return errors.Join(errs...)
+
+	case WriteBehind:
+		errs := []error{layerError(c.Caches[0], action, set(ctx, c.Caches[0]))}
+
+		detached := detachedContext{ctx}
+		for _, cache := range c.Caches[1:] {
+			cache := cache
+			queued := c.writes.push(func() {
+				c.writeBehind(detached, cache, action, set)
+			})
+			if !queued {
+				errs = append(errs, layerError(cache, action, ErrChainClosed))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	errs := []error{}
+	for _, cache := range c.Caches {
+		if err := set(ctx, cache); err != nil {
+			errs = append(errs, layerError(cache, action, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeBehind sets values in the given cache using the given set function,
+// retrying as configured by the WriteBehind options
+func (c *ChainCache[T]) writeBehind(ctx context.Context, cache SetterCacheInterface[T], action string, set layerWrite[T]) {
+	options := c.Options.WriteBehind
+
+	err := set(ctx, cache)
+	for retry := 0; err != nil && retry < options.Retries; retry++ {
+		time.Sleep(options.RetryDelay)
+		err = set(ctx, cache)
+	}
+
+	if err != nil && options.OnError != nil {
+		options.OnError(layerError(cache, action, err))
+	}
+}
+
+// layerWrite writes in the given cache of a chain
+type layerWrite[T any] func(ctx context.Context, cache SetterCacheInterface[T]) error
+
+// layerError returns the given error of the given cache of a chain wrapped
+// along with its store type, or nil when there is no error
+func layerError[T any](cache SetterCacheInterface[T], action string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Caches without codec (a typed marshaler over a chain for instance) are
+	// reported by their type
+	var storeType string
+	if codec := cache.GetCodec(); codec != nil {
+		storeType = codec.GetStore().GetType()
+	} else {
+		storeType = cache.GetType()
+	}
+
+	return fmt.Errorf("Unable to %s cache with store '%s': %w", action, storeType, err)
 }
 
 // Delete removes a value from all available caches
@@ -101,15 +464,43 @@ func (c *ChainCache[T]) Delete(ctx context.Context, key any) error {
 	return nil
 }
 
-// Invalidate invalidates cache item from given options
-func (c *ChainCache[T]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+// DeleteMany removes values from all available caches
+func (c *ChainCache[T]) DeleteMany(ctx context.Context, keys []any) error {
 	for _, cache := range c.Caches {
-		_ = cache.Invalidate(ctx, options...)
+		_ = cache.DeleteMany(ctx, keys)
 	}
 
 	return nil
 }
 
+// Invalidate invalidates cache items from given options in all available
+// caches. When a result is requested using store.WithInvalidateResult, the
+// result of each cache is reported in its layers.
+func (c *ChainCache[T]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	result := store.ApplyInvalidateOptions(options...).Result
+
+	errs := []error{}
+	for _, cache := range c.Caches {
+		layerOptions := options
+
+		var layerResult *store.InvalidateResult
+		if result != nil {
+			layerResult = &store.InvalidateResult{}
+			layerOptions = append(options[:len(options):len(options)], store.WithInvalidateResult(layerResult))
+		}
+
+		if err := cache.Invalidate(ctx, layerOptions...); err != nil {
+			errs = append(errs, layerError(cache, "invalidate items from", err))
+		}
+
+		if layerResult != nil {
+			result.AddLayer(layerResult)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Clear resets all cache data
 func (c *ChainCache[T]) Clear(ctx context.Context) error {
 	for _, cache := range c.Caches {
@@ -119,6 +510,54 @@ func (c *ChainCache[T]) Clear(ctx context.Context) error {
 	return nil
 }
 
+// InvalidateNamespace invalidates all the values of the given versioned
+// namespace in the caches supporting versioned namespaces
+func (c *ChainCache[T]) InvalidateNamespace(ctx context.Context, namespace string) error {
+	errs := []error{}
+	for _, cache := range c.Caches {
+		if invalidator, ok := cache.(NamespaceInvalidator); ok {
+			if err := invalidator.InvalidateNamespace(ctx, namespace); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetStats returns some statistics about the current chain cache
+func (c *ChainCache[T]) GetStats() *ChainStats {
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	stats := *c.stats
+	stats.LayerHits = append([]int{}, c.stats.LayerHits...)
+	return &stats
+}
+
+func (c *ChainCache[T]) recordHits(layer int, count int) {
+	c.statsMtx.Lock()
+	c.stats.LayerHits[layer] += count
+	c.statsMtx.Unlock()
+}
+
+func (c *ChainCache[T]) recordMisses(count int) {
+	c.statsMtx.Lock()
+	c.stats.Misses += count
+	c.statsMtx.Unlock()
+}
+
+func (c *ChainCache[T]) recordHedgedRead() {
+	c.statsMtx.Lock()
+	c.stats.HedgedReads++
+	c.statsMtx.Unlock()
+}
+
+func (c *ChainCache[T]) recordBackfillRejected() {
+	c.statsMtx.Lock()
+	c.stats.BackfillsRejected++
+	c.statsMtx.Unlock()
+}
+
 // GetCaches returns all Chained caches
 func (c *ChainCache[T]) GetCaches() []SetterCacheInterface[T] {
 	return c.Caches
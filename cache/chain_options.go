@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"time"
+)
+
+// ChainOption represents a chain cache option function.
+type ChainOption func(o *ChainOptions)
+
+type ChainOptions struct {
+	// BackfillPolicies are the policies of the caches of the chain, indexed
+	// by their position in the chain
+	BackfillPolicies map[int]BackfillPolicy
+	// Queue defines how values found in a cache are set back in the previous
+	// caches of the chain
+	Queue QueueOptions
+	// WritePolicy defines how values are set in the caches of the chain
+	WritePolicy WritePolicy
+	// WriteBehind defines how values are set in the next caches of the chain
+	// using the WriteBehind policy
+	WriteBehind WriteBehindOptions
+	// HedgeDelay is the time waited for a cache of the chain to answer before
+	// also requesting the next one, zero to request them one after another
+	HedgeDelay time.Duration
+	// Admission defines when values found in a cache are set back in the
+	// previous caches of the chain, nil to always set them back
+	Admission *AdmissionOptions
+}
+
+// WritePolicy defines how values are set in the caches of a chain.
+type WritePolicy int
+
+const (
+	// WriteThrough sets values in all the caches of the chain, in order. It is
+	// the default policy.
+	WriteThrough WritePolicy = iota
+	// WriteThroughParallel sets values in all the caches of the chain
+	// concurrently
+	WriteThroughParallel
+	// WriteAround sets values in the last cache of the chain only, which is
+	// the authoritative one, and deletes them from the previous caches
+	WriteAround
+	// WriteBehind sets values in the first cache of the chain, and in the next
+	// caches in background (see WriteBehindOptions)
+	WriteBehind
+)
+
+// WriteBehindOptions defines how values are set in the next caches of a chain
+// using the WriteBehind policy.
+type WriteBehindOptions struct {
+	// Queue defines how values are queued to be set in the next caches. Its
+	// DropOnFull option is ignored: writes wait for a free slot instead of
+	// being dropped, and fail with ErrChainClosed once the chain is closed.
+	Queue QueueOptions
+	// Retries is the number of times a failed write is retried
+	Retries int
+	// RetryDelay is the time waited before retrying a failed write
+	RetryDelay time.Duration
+	// OnError is called with the error of the writes still failing once
+	// retried, if any
+	OnError func(err error)
+}
+
+// BackfillPolicy defines how a value found in a cache of a chain is set back
+// in a previous cache of the chain.
+type BackfillPolicy struct {
+	// MaxTTL caps the time to live of the values set back in the cache
+	MaxTTL time.Duration
+	// TTLFraction is the fraction of the remaining time to live of a value
+	// used as its time to live when set back in the cache (0.5 for half of
+	// it). The whole remaining time to live is used when zero.
+	TTLFraction float64
+	// DefaultTTL is the time to live of the values set back in the cache
+	// when the cache they were found in reports none (Bigcache, Ristretto,
+	// ...) or when read using GetMany. The default expiration of the store of
+	// the cache is used when zero.
+	DefaultTTL time.Duration
+	// PropagateTags sets the values back along with their tags. It requires
+	// the store of the cache they were found in to keep them (see
+	// store.TagsGetter), as the memory store does but Redis or Memcache
+	// don't: values whose tags can't be read are not set back, and OnError
+	// is called with an error wrapping store.ErrTagsNotSupported.
+	PropagateTags bool
+	// OnError is called with the error of the values failing to be set back
+	// in the cache, if any
+	OnError func(err error)
+}
+
+func (p BackfillPolicy) onError(err error) {
+	if err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// ttl returns the time to live of a value set back using the policy, given
+// its remaining time to live in the cache it was found in (zero or negative
+// when unknown)
+func (p BackfillPolicy) ttl(remaining time.Duration) time.Duration {
+	ttl := remaining
+
+	switch {
+	case ttl <= 0:
+		ttl = p.DefaultTTL
+	case p.TTLFraction > 0:
+		ttl = time.Duration(float64(ttl) * p.TTLFraction)
+	}
+
+	if p.MaxTTL > 0 && (ttl <= 0 || ttl > p.MaxTTL) {
+		ttl = p.MaxTTL
+	}
+
+	return ttl
+}
+
+func applyChainOptions(opts ...ChainOption) *ChainOptions {
+	o := &ChainOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// backfillPolicy returns the policy of the cache at the given position in the
+// chain
+func (o *ChainOptions) backfillPolicy(layer int) BackfillPolicy {
+	if o == nil {
+		return BackfillPolicy{}
+	}
+
+	return o.BackfillPolicies[layer]
+}
+
+// WithBackfillPolicy allows to specify how values found in the next caches of
+// the chain are set back in the cache at the given position in the chain (0
+// for the first one). By default, values are set back using the remaining time
+// to live reported by the cache they were found in, and without tags.
+func WithBackfillPolicy(layer int, policy BackfillPolicy) ChainOption {
+	return func(o *ChainOptions) {
+		if o.BackfillPolicies == nil {
+			o.BackfillPolicies = map[int]BackfillPolicy{}
+		}
+		o.BackfillPolicies[layer] = policy
+	}
+}
+
+// WithBackfillQueue allows to specify the size of the queue of values set back
+// in the previous caches of the chain, the number of goroutines setting them,
+// and whether values are dropped when the queue is full or set synchronously.
+func WithBackfillQueue(options QueueOptions) ChainOption {
+	return func(o *ChainOptions) {
+		o.Queue = options
+	}
+}
+
+// WithWritePolicy allows to specify how values are set in the caches of the
+// chain. By default, they are set in all the caches, in order (WriteThrough).
+func WithWritePolicy(policy WritePolicy) ChainOption {
+	return func(o *ChainOptions) {
+		o.WritePolicy = policy
+	}
+}
+
+// WithWriteBehind allows to set values in the first cache of the chain, and in
+// the next caches in background, retrying failed writes as given.
+func WithWriteBehind(options WriteBehindOptions) ChainOption {
+	return func(o *ChainOptions) {
+		o.WritePolicy = WriteBehind
+		o.WriteBehind = options
+	}
+}
+
+// WithHedgedReads allows to request the next cache of the chain when a cache
+// hasn't answered within the given delay, without waiting for its answer: the
+// first value found is returned, and the other requests are cancelled through
+// their context. By default, caches are requested one after another.
+func WithHedgedReads(delay time.Duration) ChainOption {
+	return func(o *ChainOptions) {
+		o.HedgeDelay = delay
+	}
+}
+
+// WithBackfillAdmission allows to only set values found in a cache back in the
+// previous caches of the chain once their key has been found in the next
+// caches often enough (see AdmissionOptions). By default, values are always set
+// back.
+func WithBackfillAdmission(options AdmissionOptions) ChainOption {
+	return func(o *ChainOptions) {
+		o.Admission = &options
+	}
+}
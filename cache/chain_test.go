@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	gocache "github.com/patrickmn/go-cache"
 	"github.com/prodadidb/gocache/cache"
 	"github.com/prodadidb/gocache/store"
 	"github.com/stretchr/testify/assert"
@@ -148,26 +149,12 @@ func TestChainGetWhenNotAvailableInAnyCache(t *testing.T) {
 	ctx := context.Background()
 
 	// Cache 1
-	store1 := NewMockStoreInterface(ctrl)
-	store1.EXPECT().GetType().Return("store1")
-
-	codec1 := NewMockCodecInterface(ctrl)
-	codec1.EXPECT().GetStore().Return(store1)
-
 	cache1 := NewMockSetterCacheInterface[any](ctrl)
-	cache1.EXPECT().GetCodec().Return(codec1)
 	cache1.EXPECT().GetWithTTL(ctx, "my-key").Return(nil, 0*time.Second,
 		errors.New("unable to find in cache 1"))
 
 	// Cache 2
-	store2 := NewMockStoreInterface(ctrl)
-	store2.EXPECT().GetType().Return("store2")
-
-	codec2 := NewMockCodecInterface(ctrl)
-	codec2.EXPECT().GetStore().Return(store2)
-
 	cache2 := NewMockSetterCacheInterface[any](ctrl)
-	cache2.EXPECT().GetCodec().Return(codec2)
 	cache2.EXPECT().GetWithTTL(ctx, "my-key").Return(nil, 0*time.Second,
 		errors.New("unable to find in cache 2"))
 
@@ -248,8 +235,32 @@ func TestChainSetWhenErrorOnSetting(t *testing.T) {
 	err := ch.Set(ctx, "my-key", cacheValue)
 
 	// Then
-	assert.Error(t, err)
-	assert.Equal(t, fmt.Sprintf("error 1 of 1: Unable to set item into cache with store 'store1': %s", expectedErr.Error()), err.Error())
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, fmt.Sprintf("Unable to set item into cache with store 'store1': %s", expectedErr.Error()), err.Error())
+}
+
+func TestChainSetWhenErrorOnSettingWithoutCodec(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("an unexpected error occurred while setting data")
+
+	// Cache 1
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetCodec().Return(nil)
+	cache1.EXPECT().GetType().Return("typed")
+	cache1.EXPECT().Set(ctx, "my-key", "my-value").Return(expectedErr)
+
+	ch := cache.NewChain[any](cache1)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, fmt.Sprintf("Unable to set item into cache with store 'typed': %s", expectedErr.Error()), err.Error())
 }
 
 func TestChainDelete(t *testing.T) {
@@ -327,9 +338,18 @@ func TestChainInvalidateWhenError(t *testing.T) {
 
 	ctx := context.Background()
 
+	expectedErr := errors.New("an unexpected error has occurred while invalidation data")
+
 	// Cache 1
+	store1 := NewMockStoreInterface(ctrl)
+	store1.EXPECT().GetType().Return("store1")
+
+	codec1 := NewMockCodecInterface(ctrl)
+	codec1.EXPECT().GetStore().Return(store1)
+
 	cache1 := NewMockSetterCacheInterface[any](ctrl)
-	cache1.EXPECT().Invalidate(ctx).Return(errors.New("an unexpected error has occurred while invalidation data"))
+	cache1.EXPECT().Invalidate(ctx).Return(expectedErr)
+	cache1.EXPECT().GetCodec().Return(codec1)
 
 	// Cache 2
 	cache2 := NewMockSetterCacheInterface[any](ctrl)
@@ -340,8 +360,40 @@ func TestChainInvalidateWhenError(t *testing.T) {
 	// When
 	err := ch.Invalidate(ctx)
 
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Contains(t, err.Error(), "Unable to invalidate items from cache with store 'store1'")
+}
+
+func TestChainInvalidateResult(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChain[any](cache.New[any](store1), cache.New[any](store2))
+
+	_ = store1.Set(ctx, "key-1", "value-1", store.WithTags([]string{"tag1"}))
+	_ = store2.Set(ctx, "key-1", "value-1", store.WithTags([]string{"tag1"}))
+	_ = store2.Set(ctx, "key-2", "value-2", store.WithTags([]string{"tag2"}))
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := ch.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2", "tag3"}), store.WithInvalidateResult(result))
+
 	// Then
 	assert.Nil(t, err)
+
+	assert.Equal(t, 3, result.Removed)
+	assert.Equal(t, map[string]int{"tag1": 2, "tag2": 1}, result.RemovedByTag)
+	assert.Equal(t, []string{"tag3"}, result.TagsNotFound)
+
+	assert.Len(t, result.Layers, 2)
+	assert.Equal(t, 1, result.Layers[0].Removed)
+	assert.Equal(t, []string{"tag2", "tag3"}, result.Layers[0].TagsNotFound)
+	assert.Equal(t, 2, result.Layers[1].Removed)
 }
 
 func TestChainClear(t *testing.T) {
@@ -450,7 +502,955 @@ func TestChainSetWhenErrorInChain(t *testing.T) {
 	// When - Then
 	err := ch.Set(ctx, key, value, nil)
 
-	expErr := errors.New("error 1 of 1: Unable to set item into cache with store 'store1': an issue occurred with the cache")
 	// Then
-	assert.Equal(t, expErr, err)
+	assert.ErrorIs(t, err, interError)
+	assert.Equal(t, "Unable to set item into cache with store 'store1': an issue occurred with the cache", err.Error())
+}
+
+func TestChainGetManyWhenAvailableInDifferentCaches(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	// Cache 1
+	store1 := NewMockStoreInterface(ctrl)
+	store1.EXPECT().GetType().AnyTimes().Return("store1")
+
+	codec1 := NewMockCodecInterface(ctrl)
+	codec1.EXPECT().GetStore().AnyTimes().Return(store1)
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetCodec().AnyTimes().Return(codec1)
+	cache1.EXPECT().GetMany(ctx, []any{"key-1", "key-2", "key-3"}).Return(map[any]any{
+		"key-1": "value-1",
+	}, nil)
+	cache1.EXPECT().Set(ctx, "key-2", "value-2", &store.OptionsMatcher{}).Return(nil)
+
+	// Cache 2
+	store2 := NewMockStoreInterface(ctrl)
+	store2.EXPECT().GetType().AnyTimes().Return("store2")
+
+	codec2 := NewMockCodecInterface(ctrl)
+	codec2.EXPECT().GetStore().AnyTimes().Return(store2)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetCodec().AnyTimes().Return(codec2)
+	cache2.EXPECT().GetMany(ctx, []any{"key-2", "key-3"}).Return(map[any]any{
+		"key-2": "value-2",
+	}, nil)
+
+	ch := cache.NewChain[any](cache1, cache2)
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Wait for data to be processed
+	time.Sleep(100 * time.Millisecond)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1", "key-2": "value-2"}, values)
+}
+
+func TestChainSetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	items := map[any]any{"key-1": "value-1", "key-2": "value-2"}
+
+	// Cache 1
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().SetMany(ctx, items).Return(nil)
+
+	// Cache 2
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().SetMany(ctx, items).Return(nil)
+
+	ch := cache.NewChain[any](cache1, cache2)
+
+	// When
+	err := ch.SetMany(ctx, items)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestChainDeleteMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	// Cache 1
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().DeleteMany(ctx, []any{"key-1", "key-2"}).Return(nil)
+
+	// Cache 2
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().DeleteMany(ctx, []any{"key-1", "key-2"}).Return(nil)
+
+	ch := cache.NewChain[any](cache1, cache2)
+
+	// When
+	err := ch.DeleteMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestChainGetWhenBackfillPolicy(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+	store3 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2), cache.New[any](store3)},
+		cache.WithBackfillPolicy(0, cache.BackfillPolicy{MaxTTL: 10 * time.Minute}),
+		cache.WithBackfillPolicy(1, cache.BackfillPolicy{TTLFraction: 0.5}),
+	)
+
+	_ = store3.Set(ctx, "my-key", "my-value", store.WithExpiration(time.Hour))
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	assert.Eventually(t, func() bool {
+		_, err1 := store1.Get(ctx, "my-key")
+		_, err2 := store2.Get(ctx, "my-key")
+		return err1 == nil && err2 == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, ttl, _ := store1.GetWithTTL(ctx, "my-key")
+	assert.LessOrEqual(t, ttl, 10*time.Minute)
+	assert.Greater(t, ttl, 9*time.Minute)
+
+	_, ttl, _ = store2.GetWithTTL(ctx, "my-key")
+	assert.LessOrEqual(t, ttl, 30*time.Minute)
+	assert.Greater(t, ttl, 29*time.Minute)
+}
+
+func TestChainGetWhenBackfillPolicyDefaultTTL(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillPolicy(0, cache.BackfillPolicy{DefaultTTL: 5 * time.Minute}),
+	)
+
+	// Stored without expiration, as done by Bigcache or Ristretto
+	_ = store2.Set(ctx, "my-key", "my-value")
+
+	// When
+	_, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, ttl, err := store1.GetWithTTL(ctx, "my-key")
+		return err == nil && ttl > 4*time.Minute && ttl <= 5*time.Minute
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestChainGetWhenBackfillPolicyPropagatesTags(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillPolicy(0, cache.BackfillPolicy{PropagateTags: true}),
+	)
+
+	_ = store2.Set(ctx, "my-key", "my-value", store.WithTags([]string{"book"}))
+
+	// When
+	_, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		tags, err := store1.GetTags(ctx, "my-key")
+		return err == nil && len(tags) == 1 && tags[0] == "book"
+	}, time.Second, 10*time.Millisecond)
+
+	err = ch.Invalidate(ctx, store.WithInvalidateTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	_, err = store1.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestChainGetWhenBackfillPolicyPropagatesTagsAndNotSupported(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewGoCache(gocache.New(5*time.Second, 5*time.Second))
+
+	var backfillErr error
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillPolicy(0, cache.BackfillPolicy{
+			PropagateTags: true,
+			OnError:       func(err error) { backfillErr = err },
+		}),
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	_ = store2.Set(ctx, "my-key", "my-value", store.WithTags([]string{"book"}))
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	assert.ErrorIs(t, backfillErr, store.ErrTagsNotSupported)
+	assert.EqualError(t, backfillErr, "Unable to read tags from cache with store 'go-cache': store can't return the tags of its values")
+
+	_, err = store1.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestChainGetManyWhenBackfillPolicy(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillPolicy(0, cache.BackfillPolicy{MaxTTL: time.Minute}),
+	)
+
+	_ = store2.Set(ctx, "key-1", "value-1")
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
+
+	assert.Eventually(t, func() bool {
+		_, ttl, err := store1.GetWithTTL(ctx, "key-1")
+		return err == nil && ttl > 0 && ttl <= time.Minute
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestChainGetManyWhenBackfillDefaultExpiration(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory(store.WithDefaultOptions(store.WithExpiration(time.Minute)))
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	_ = store2.Set(ctx, "key-1", "value-1")
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
+
+	_, ttl, err := store1.GetWithTTL(ctx, "key-1")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute)
+}
+
+func TestChainGetWhenSyncBackfill(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	_ = store2.Set(ctx, "my-key", "my-value")
+
+	// When
+	_, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Nil(t, ch.SetChannel)
+
+	value, err := store1.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestChainGetWhenBackfillQueueFull(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(ctx, gomock.Any()).Return(nil, time.Duration(0), errors.New("unable to find in cache 1")).Times(3)
+	cache1.EXPECT().Set(gomock.Any(), gomock.Any(), "my-value", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ any, _ any, _ ...store.Option) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}).Times(2)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetWithTTL(ctx, gomock.Any()).Return("my-value", time.Duration(0), nil).Times(3)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithBackfillQueue(cache.QueueOptions{Size: 1, DropOnFull: true}),
+	)
+
+	// When
+	_, err := ch.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	<-started
+
+	_, err = ch.Get(ctx, "key-2")
+	assert.Nil(t, err)
+
+	_, err = ch.Get(ctx, "key-3")
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, uint64(1), ch.Dropped())
+
+	close(release)
+	assert.Nil(t, ch.Close())
+}
+
+func TestChainClose(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChain[any](cache.New[any](store1), cache.New[any](store2))
+
+	_ = store2.Set(ctx, "key-1", "value-1")
+	_ = store2.Set(ctx, "key-2", "value-2")
+
+	_, err := ch.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	// When
+	err = ch.Close()
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = store1.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	value, err := ch.Get(ctx, "key-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-2", value)
+
+	_, err = store1.Get(ctx, "key-2")
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Equal(t, uint64(1), ch.Dropped())
+
+	assert.Nil(t, ch.Close())
+}
+
+func TestChainSetWhenWriteThroughParallel(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	err1 := errors.New("unable to set in cache 1")
+	err2 := errors.New("unable to set in cache 2")
+
+	store1 := NewMockStoreInterface(ctrl)
+	store1.EXPECT().GetType().Return("store1")
+	codec1 := NewMockCodecInterface(ctrl)
+	codec1.EXPECT().GetStore().Return(store1)
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetCodec().Return(codec1)
+	cache1.EXPECT().Set(ctx, "my-key", "my-value").Return(err1)
+
+	store2 := NewMockStoreInterface(ctrl)
+	store2.EXPECT().GetType().Return("store2")
+	codec2 := NewMockCodecInterface(ctrl)
+	codec2.EXPECT().GetStore().Return(store2)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetCodec().Return(codec2)
+	cache2.EXPECT().Set(ctx, "my-key", "my-value").Return(err2)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithWritePolicy(cache.WriteThroughParallel),
+	)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.ErrorIs(t, err, err1)
+	assert.ErrorIs(t, err, err2)
+	assert.Equal(t, "Unable to set item into cache with store 'store1': unable to set in cache 1\n"+
+		"Unable to set item into cache with store 'store2': unable to set in cache 2", err.Error())
+}
+
+func TestChainSetWhenWriteAround(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithWritePolicy(cache.WriteAround),
+	)
+
+	_ = store1.Set(ctx, "my-key", "my-old-value")
+	_ = store2.Set(ctx, "my-key", "my-old-value")
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = store1.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	value, err := store2.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestChainSetManyWhenWriteAround(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithWritePolicy(cache.WriteAround),
+	)
+
+	_ = store1.Set(ctx, "key-1", "old-value-1")
+
+	// When
+	err := ch.SetMany(ctx, map[any]any{"key-1": "value-1", "key-2": "value-2"})
+
+	// Then
+	assert.Nil(t, err)
+
+	values, err := store1.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	values, err = store2.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1", "key-2": "value-2"}, values)
+}
+
+func TestChainSetWhenWriteAroundAndErrorOnLastCache(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to set in cache 2")
+
+	// Cache 1 is not written
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+
+	store2 := NewMockStoreInterface(ctrl)
+	store2.EXPECT().GetType().Return("store2")
+	codec2 := NewMockCodecInterface(ctrl)
+	codec2.EXPECT().GetStore().Return(store2)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetCodec().Return(codec2)
+	cache2.EXPECT().Set(ctx, "my-key", "my-value").Return(expectedErr)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithWritePolicy(cache.WriteAround),
+	)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestChainSetWhenWriteBehind(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithWriteBehind(cache.WriteBehindOptions{}),
+	)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value", store.WithExpiration(time.Minute))
+
+	// Then
+	assert.Nil(t, err)
+
+	value, err := store1.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	assert.Eventually(t, func() bool {
+		_, ttl, err := store2.GetWithTTL(ctx, "my-key")
+		return err == nil && ttl > 0 && ttl <= time.Minute
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, ch.Close())
+}
+
+func TestChainSetWhenWriteBehindAndBackfillDropOnFull(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Size: 1, DropOnFull: true}),
+		cache.WithWriteBehind(cache.WriteBehindOptions{
+			Queue: cache.QueueOptions{Size: 1, DropOnFull: true},
+		}),
+	)
+
+	// When
+	for i := 0; i < 100; i++ {
+		err := ch.Set(ctx, fmt.Sprintf("key-%d", i), "my-value")
+		assert.Nil(t, err)
+	}
+
+	// Then
+	assert.Nil(t, ch.Close())
+	assert.Equal(t, uint64(0), ch.Dropped())
+
+	for i := 0; i < 100; i++ {
+		_, err := store2.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.Nil(t, err)
+	}
+}
+
+func TestChainSetWhenWriteBehindAndClosed(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithWriteBehind(cache.WriteBehindOptions{}),
+	)
+	assert.Nil(t, ch.Close())
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.ErrorIs(t, err, cache.ErrChainClosed)
+
+	_, err = store2.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestChainSetWhenWriteBehindRetries(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to set in cache 2")
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Set(ctx, "my-key", "my-value").Return(nil)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	gomock.InOrder(
+		cache2.EXPECT().Set(gomock.Any(), "my-key", "my-value").Return(expectedErr).Times(2),
+		cache2.EXPECT().Set(gomock.Any(), "my-key", "my-value").Return(nil),
+	)
+
+	var errs []error
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithWriteBehind(cache.WriteBehindOptions{
+			Queue:      cache.QueueOptions{Sync: true},
+			Retries:    2,
+			RetryDelay: time.Millisecond,
+			OnError: func(err error) {
+				errs = append(errs, err)
+			},
+		}),
+	)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestChainSetWhenWriteBehindFails(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to set in cache 2")
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Set(ctx, "my-key", "my-value").Return(nil)
+
+	store2 := NewMockStoreInterface(ctrl)
+	store2.EXPECT().GetType().Return("store2")
+	codec2 := NewMockCodecInterface(ctrl)
+	codec2.EXPECT().GetStore().Return(store2)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetCodec().Return(codec2)
+	cache2.EXPECT().Set(gomock.Any(), "my-key", "my-value").Return(expectedErr).Times(2)
+
+	var errs []error
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithWriteBehind(cache.WriteBehindOptions{
+			Queue:   cache.QueueOptions{Sync: true},
+			Retries: 1,
+			OnError: func(err error) {
+				errs = append(errs, err)
+			},
+		}),
+	)
+
+	// When
+	err := ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], expectedErr)
+	assert.Equal(t, "Unable to set item into cache with store 'store2': unable to set in cache 2", errs[0].Error())
+}
+
+func TestChainGetStats(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	_ = store2.Set(ctx, "key-1", "value-1")
+	_ = store2.Set(ctx, "key-2", "value-2")
+
+	// When
+	_, _ = ch.Get(ctx, "key-1")
+	_, _ = ch.Get(ctx, "key-1")
+	_, _ = ch.Get(ctx, "key-3")
+	_, _ = ch.GetMany(ctx, []any{"key-1", "key-2", "key-4"})
+
+	// Then
+	assert.Equal(t, &cache.ChainStats{
+		LayerHits: []int{2, 2},
+		Misses:    2,
+	}, ch.GetStats())
+}
+
+func TestChainGetWhenHedgedReads(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cancelled := make(chan struct{})
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(gomock.Any(), "my-key").
+		DoAndReturn(func(ctx context.Context, _ any) (any, time.Duration, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, time.Duration(0), ctx.Err()
+		})
+	cache1.EXPECT().Set(gomock.Any(), "my-key", "my-value", gomock.Any()).Return(nil)
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetWithTTL(gomock.Any(), "my-key").Return("my-value", time.Minute, nil)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+		cache.WithHedgedReads(10*time.Millisecond),
+	)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("request to the first cache has not been cancelled")
+	}
+
+	assert.Equal(t, &cache.ChainStats{
+		LayerHits:   []int{0, 1},
+		HedgedReads: 1,
+	}, ch.GetStats())
+}
+
+func TestChainGetWhenHedgedReadsAndAvailableInFirstCache(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(gomock.Any(), "my-key").Return("my-value", time.Duration(0), nil)
+
+	// Cache 2 is not requested
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithHedgedReads(time.Second),
+	)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+	assert.Equal(t, &cache.ChainStats{LayerHits: []int{1, 0}}, ch.GetStats())
+}
+
+func TestChainGetWhenHedgedReadsAndNotAvailableInAnyCache(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to find in cache 2")
+
+	// Misses are answered before the hedge delay: caches are requested one
+	// after another
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(gomock.Any(), "my-key").Return(nil, time.Duration(0), errors.New("unable to find in cache 1"))
+
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+	cache2.EXPECT().GetWithTTL(gomock.Any(), "my-key").Return(nil, time.Duration(0), expectedErr)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithHedgedReads(time.Second),
+	)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, &cache.ChainStats{LayerHits: []int{0, 0}, Misses: 1}, ch.GetStats())
+}
+
+func TestChainGetWhenHedgedReadsAndContextCancelled(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(gomock.Any(), "my-key").
+		DoAndReturn(func(ctx context.Context, _ any) (any, time.Duration, error) {
+			<-ctx.Done()
+			return nil, time.Duration(0), ctx.Err()
+		})
+
+	// Cache 2 is not requested
+	cache2 := NewMockSetterCacheInterface[any](ctrl)
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache1, cache2},
+		cache.WithHedgedReads(time.Second),
+	)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestChainGetWhenBackfillAdmission(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+		cache.WithBackfillAdmission(cache.AdmissionOptions{MinHits: 3, Counters: 1024}),
+	)
+
+	_ = store2.Set(ctx, "hot-key", "hot-value")
+	_ = store2.Set(ctx, "cold-key", "cold-value")
+
+	// When - Then
+	for i := 0; i < 2; i++ {
+		value, err := ch.Get(ctx, "hot-key")
+		assert.Nil(t, err)
+		assert.Equal(t, "hot-value", value)
+
+		_, err = store1.Get(ctx, "hot-key")
+		assert.ErrorIs(t, err, store.NotFound{})
+	}
+
+	_, err := ch.Get(ctx, "cold-key")
+	assert.Nil(t, err)
+
+	_, err = ch.Get(ctx, "hot-key")
+	assert.Nil(t, err)
+
+	value, err := store1.Get(ctx, "hot-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "hot-value", value)
+
+	_, err = store1.Get(ctx, "cold-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	assert.Equal(t, 3, ch.GetStats().BackfillsRejected)
+}
+
+func TestChainGetWhenBackfillAdmissionWindowElapsed(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+		cache.WithBackfillAdmission(cache.AdmissionOptions{MinHits: 2, Window: 20 * time.Millisecond}),
+	)
+
+	_ = store2.Set(ctx, "my-key", "my-value")
+
+	_, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// When
+	_, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = store1.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	_, err = ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	_, err = store1.Get(ctx, "my-key")
+	assert.Nil(t, err)
+}
+
+func TestChainGetManyWhenBackfillAdmission(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	ch := cache.NewChainWithOptions[any](
+		[]cache.SetterCacheInterface[any]{cache.New[any](store1), cache.New[any](store2)},
+		cache.WithBackfillQueue(cache.QueueOptions{Sync: true}),
+		cache.WithBackfillAdmission(cache.AdmissionOptions{MinHits: 2}),
+	)
+
+	_ = store2.Set(ctx, "key-1", "value-1")
+	_ = store2.Set(ctx, "key-2", "value-2")
+
+	_, err := ch.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1", "key-2": "value-2"}, values)
+
+	values, err = store1.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
 }
@@ -13,8 +13,11 @@ import (
 // CacheInterface represents the interface for all caches (aggregates, metric, memory, redis, ...)
 type CacheInterface[T any] interface {
 	Get(ctx context.Context, key any) (T, error)
+	GetMany(ctx context.Context, keys []any) (map[any]T, error)
 	Set(ctx context.Context, key any, object T, options ...store.Option) error
+	SetMany(ctx context.Context, items map[any]T, options ...store.Option) error
 	Delete(ctx context.Context, key any) error
+	DeleteMany(ctx context.Context, keys []any) error
 	Invalidate(ctx context.Context, options ...store.InvalidateOption) error
 	Clear(ctx context.Context) error
 	GetType() string
@@ -29,8 +32,11 @@ type CacheKeyGenerator interface {
 type SetterCacheInterface[T any] interface {
 	// CacheInterface[T] TODO: Waiting for gomock to support nested interfaces with generics.
 	Get(ctx context.Context, key any) (T, error)
+	GetMany(ctx context.Context, keys []any) (map[any]T, error)
 	Set(ctx context.Context, key any, object T, options ...store.Option) error
+	SetMany(ctx context.Context, items map[any]T, options ...store.Option) error
 	Delete(ctx context.Context, key any) error
+	DeleteMany(ctx context.Context, keys []any) error
 	Invalidate(ctx context.Context, options ...store.InvalidateOption) error
 	Clear(ctx context.Context) error
 	GetType() string
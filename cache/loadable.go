@@ -2,9 +2,13 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/prodadidb/gocache/store"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -12,42 +16,108 @@ const (
 	LoadableType = "loadable"
 )
 
+// ErrStaleValue is wrapped by the error returned along with a stale value,
+// when the load function failed to reload an expired entry
+var ErrStaleValue = errors.New("stale value returned after a load failure")
+
+var errNegativeEntry = errors.New("value not found by load function")
+
 type loadableKeyValue[T any] struct {
-	key   any
-	value T
+	key      any
+	value    T
+	options  []store.Option
+	notFound bool
 }
 
 type LoadFunction[T any] func(ctx context.Context, key any) (T, error)
 
+// LoadFunctionWithOptions is a load function that also returns the store
+// options (expiration, cost, tags, ...) to use when setting the loaded value
+// back in cache
+type LoadFunctionWithOptions[T any] func(ctx context.Context, key any) (T, []store.Option, error)
+
+// LoadableStats allows to returns some statistics of loadable cache usage
+type LoadableStats struct {
+	Loads            int
+	LoadErrors       int
+	LoadDeduplicated int
+	Refreshes        int
+	StaleServes      int
+	NegativeHits     int
+	BatchLoads       int
+	SetsDropped      int
+	// SetErrors is the number of loaded values failing to be set back in
+	// cache
+	SetErrors int
+}
+
 // LoadableCache represents a cache that uses a function to load data
 type LoadableCache[T any] struct {
-	LoadFunc   LoadFunction[T]
-	Cache      CacheInterface[T]
-	SetChannel chan *loadableKeyValue[T]
-	SetterWg   *sync.WaitGroup
+	LoadFunc            LoadFunction[T]
+	LoadFuncWithOptions LoadFunctionWithOptions[T]
+	BatchLoadFunc       BatchLoadFunction[T]
+	Cache               CacheInterface[T]
+	SetChannel          chan *loadableKeyValue[T]
+	SetterWg            *sync.WaitGroup
+	Options             *LoadableOptions
+
+	queue      *queue[*loadableKeyValue[T]]
+	loadGroup  singleflight.Group
+	batcher    *loadableBatcher[T]
+	refreshing sync.Map
+	stats      *LoadableStats
+	statsMtx   sync.Mutex
 }
 
-// NewLoadable instanciates a new cache that uses a function to load data
-func NewLoadable[T any](loadFunc LoadFunction[T], cache CacheInterface[T]) *LoadableCache[T] {
+// NewLoadable instanciates a new cache that uses a function to load data.
+// Soft TTL, refresh ahead, max staleness and negative caching store metadata
+// along with the values, which requires values of type []byte or string, or a
+// cache typed with an interface (see encodeLoadableEntry): setting other
+// values returns ErrLoadableEntryNotSupported, and loaded values (or negative
+// entries) failing to be set back in cache are counted by the SetErrors
+// statistic.
+func NewLoadable[T any](loadFunc LoadFunction[T], cache CacheInterface[T], options ...LoadableOption) *LoadableCache[T] {
 	loadable := &LoadableCache[T]{
-		LoadFunc:   loadFunc,
-		Cache:      cache,
-		SetChannel: make(chan *loadableKeyValue[T], 10000),
-		SetterWg:   &sync.WaitGroup{},
+		LoadFunc: loadFunc,
+		Cache:    cache,
+		Options:  applyLoadableOptions(options...),
+		stats:    &LoadableStats{},
 	}
 
-	loadable.SetterWg.Add(1)
-	go loadable.setter()
+	loadable.queue = newQueue(loadable.Options.Queue, loadable.setLoaded)
+	loadable.SetChannel = loadable.queue.items
+	loadable.SetterWg = loadable.queue.wg
+
+	return loadable
+}
+
+// NewLoadableWithOptions instanciates a new cache that uses a function to load
+// data along with the store options to use when setting them back in cache
+func NewLoadableWithOptions[T any](loadFunc LoadFunctionWithOptions[T], cache CacheInterface[T], options ...LoadableOption) *LoadableCache[T] {
+	loadable := NewLoadable[T](nil, cache, options...)
+	loadable.LoadFuncWithOptions = loadFunc
 
 	return loadable
 }
 
-func (c *LoadableCache[T]) setter() {
-	defer c.SetterWg.Done()
+// setLoaded sets the given loaded value back in cache
+func (c *LoadableCache[T]) setLoaded(item *loadableKeyValue[T]) {
+	if item.notFound {
+		c.recordSetError(c.setNotFound(context.Background(), item.key, item.options...))
+		return
+	}
 
-	for item := range c.SetChannel {
-		_ = c.Set(context.Background(), item.key, item.value)
+	c.recordSetError(c.Set(context.Background(), item.key, item.value, c.loadedOptions(item.options)...))
+}
+
+// loadedOptions returns the store options used to set loaded values back in
+// cache, the ones returned by the load function taking precedence
+func (c *LoadableCache[T]) loadedOptions(options []store.Option) []store.Option {
+	if c.Options.Expiration > 0 {
+		return append([]store.Option{store.WithExpiration(c.Options.Expiration)}, options...)
 	}
+
+	return options
 }
 
 // Get returns the object stored in cache if it exists
@@ -56,24 +126,259 @@ func (c *LoadableCache[T]) Get(ctx context.Context, key any) (T, error) {
 
 	object, err := c.Cache.Get(ctx, key)
 	if err == nil {
-		return object, err
+		return c.fromCache(ctx, key, object)
 	}
 
 	// Unable to find in cache, try to load it from load function
-	object, err = c.LoadFunc(ctx, key)
-	if err != nil {
+	return c.load(ctx, key)
+}
+
+// fromCache returns the value of an object found in cache, depending on its
+// metadata when it has been set as an entry
+func (c *LoadableCache[T]) fromCache(ctx context.Context, key any, object T) (T, error) {
+	entry, ok := decodeLoadableEntry(object)
+	if !ok {
+		return object, nil
+	}
+
+	if entry.NotFound {
+		c.statsMtx.Lock()
+		c.stats.NegativeHits++
+		c.statsMtx.Unlock()
+
+		return *new(T), store.NotFoundWithCause(errNegativeEntry)
+	}
+
+	if c.isExpired(entry) {
+		return c.loadOrServeStale(ctx, key, entry)
+	}
+
+	if c.needsRefresh(entry) {
+		c.refresh(ctx, key)
+	}
+
+	return entry.Value, nil
+}
+
+// load calls the load function for the given key, sharing a single call
+// between all concurrent callers asking for the same cache key.
+// The shared call is not cancelled when a caller context is done: only this
+// caller stops waiting for the result.
+func (c *LoadableCache[T]) load(ctx context.Context, key any) (T, error) {
+	executed := false
+
+	resultChan := c.loadGroup.DoChan(c.getCacheKey(key), func() (any, error) {
+		executed = true
+
+		object, options, err := c.callLoadFunc(detachedContext{ctx}, key)
+		if c.isNegative(err) {
+			c.queue.push(&loadableKeyValue[T]{key: key, options: options, notFound: true})
+		}
+		if err != nil {
+			return object, err
+		}
+
+		// Then, put it back in cache
+		c.queue.push(&loadableKeyValue[T]{key: key, value: object, options: options})
+
+		return object, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+
+	case result := <-resultChan:
+		if !executed {
+			c.statsMtx.Lock()
+			c.stats.LoadDeduplicated++
+			c.statsMtx.Unlock()
+		}
+
+		object, _ := result.Val.(T)
+		return object, result.Err
+	}
+}
+
+// loadOrServeStale loads the value of an expired entry, or returns the stale
+// value when the load function fails and the entry is still in its grace period
+func (c *LoadableCache[T]) loadOrServeStale(ctx context.Context, key any, entry *loadableEntry[T]) (T, error) {
+	object, err := c.load(ctx, key)
+	if err == nil || ctx.Err() != nil || errors.Is(err, store.NotFound{}) ||
+		time.Now().After(entry.HardDeadline.Add(c.Options.MaxStaleness)) {
 		return object, err
 	}
 
-	// Then, put it back in cache
-	c.SetChannel <- &loadableKeyValue[T]{key, object}
+	c.statsMtx.Lock()
+	c.stats.StaleServes++
+	c.statsMtx.Unlock()
+
+	return entry.Value, fmt.Errorf("%w: %w", ErrStaleValue, err)
+}
+
+// callLoadFunc calls the load function and records its statistics
+func (c *LoadableCache[T]) callLoadFunc(ctx context.Context, key any) (T, []store.Option, error) {
+	if c.BatchLoadFunc != nil {
+		// Statistics are recorded by the batch itself
+		object, err := c.batcher.load(ctx, key)
+		return object, nil, err
+	}
+
+	var (
+		object  T
+		options []store.Option
+		err     error
+	)
+
+	if c.LoadFuncWithOptions != nil {
+		object, options, err = c.LoadFuncWithOptions(ctx, key)
+	} else {
+		object, err = c.LoadFunc(ctx, key)
+	}
+
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	c.stats.Loads++
+	if err != nil {
+		c.stats.LoadErrors++
+	}
+
+	return object, options, err
+}
+
+// isNegative returns true when the given load function error has to be cached
+// as a negative entry
+func (c *LoadableCache[T]) isNegative(err error) bool {
+	return err != nil && c.Options.NegativeTTL > 0 && errors.Is(err, store.NotFound{})
+}
 
-	return object, err
+// isExpired returns true when the given entry is past its expiration and is
+// only kept in cache because of the max staleness grace period
+func (c *LoadableCache[T]) isExpired(entry *loadableEntry[T]) bool {
+	return c.Options.MaxStaleness > 0 && !entry.HardDeadline.IsZero() && time.Now().After(entry.HardDeadline)
+}
+
+// needsRefresh returns true when the given entry is past its soft deadline
+// or within the refresh-ahead window of its expiration
+func (c *LoadableCache[T]) needsRefresh(entry *loadableEntry[T]) bool {
+	now := time.Now()
+
+	if !entry.SoftDeadline.IsZero() && now.After(entry.SoftDeadline) {
+		return true
+	}
+
+	return c.Options.RefreshAhead > 0 && !entry.HardDeadline.IsZero() &&
+		entry.HardDeadline.Sub(now) <= c.Options.RefreshAhead
+}
+
+// refresh loads the value of the given key in background and sets it back in
+// cache. Only one refresh runs at a time for a given cache key.
+func (c *LoadableCache[T]) refresh(ctx context.Context, key any) {
+	cacheKey := c.getCacheKey(key)
+	if _, running := c.refreshing.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	c.statsMtx.Lock()
+	c.stats.Refreshes++
+	c.statsMtx.Unlock()
+
+	go func() {
+		defer c.refreshing.Delete(cacheKey)
+
+		ctx := detachedContext{ctx}
+
+		object, options, err := c.callLoadFunc(ctx, key)
+		if c.isNegative(err) {
+			c.recordSetError(c.setNotFound(ctx, key, options...))
+		}
+		if err != nil {
+			return
+		}
+
+		c.recordSetError(c.Set(ctx, key, object, c.loadedOptions(options)...))
+	}()
+}
+
+// getCacheKey returns the key used to deduplicate concurrent loads, using the
+// underlying cache key generation when available
+func (c *LoadableCache[T]) getCacheKey(key any) string {
+	if generator, ok := c.Cache.(interface{ GetCacheKey(key any) string }); ok {
+		return generator.GetCacheKey(key)
+	}
+
+	return getCacheKey(key)
 }
 
 // Set sets a value in available caches
 func (c *LoadableCache[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
-	return c.Cache.Set(ctx, key, object, options...)
+	if c.Options.usesEntries() {
+		value, err := c.newEntry(object, options...)
+		if err != nil {
+			return err
+		}
+		object = value
+	}
+
+	return c.Cache.Set(ctx, key, object, c.setOptions(options)...)
+}
+
+// SetMany sets values in available caches
+func (c *LoadableCache[T]) SetMany(ctx context.Context, items map[any]T, options ...store.Option) error {
+	if c.Options.usesEntries() {
+		entries := make(map[any]T, len(items))
+		for key, object := range items {
+			value, err := c.newEntry(object, options...)
+			if err != nil {
+				return err
+			}
+			entries[key] = value
+		}
+		items = entries
+	}
+
+	return c.Cache.SetMany(ctx, items, c.setOptions(options)...)
+}
+
+// setOptions returns the store options used to set a value in cache
+func (c *LoadableCache[T]) setOptions(options []store.Option) []store.Option {
+	if c.Options.MaxStaleness > 0 {
+		// Keep the entry in cache during the grace period after its expiration
+		if expiration := store.ApplyOptionsWithDefault(&store.Options{}, options...).Expiration; expiration > 0 {
+			options = append(append([]store.Option{}, options...), store.WithExpiration(expiration+c.Options.MaxStaleness))
+		}
+	}
+
+	return options
+}
+
+// newEntry wraps the given object along with its soft and hard deadlines
+func (c *LoadableCache[T]) newEntry(object T, options ...store.Option) (T, error) {
+	now := time.Now()
+	entry := &loadableEntry[T]{Value: object}
+
+	if c.Options.SoftTTL > 0 {
+		entry.SoftDeadline = now.Add(c.Options.SoftTTL)
+	}
+
+	if expiration := store.ApplyOptionsWithDefault(&store.Options{}, options...).Expiration; expiration > 0 {
+		entry.HardDeadline = now.Add(expiration)
+	}
+
+	return encodeLoadableEntry(entry)
+}
+
+// setNotFound sets a negative entry for the given key, using the given store
+// options along with the negative caching ones
+func (c *LoadableCache[T]) setNotFound(ctx context.Context, key any, options ...store.Option) error {
+	value, err := encodeLoadableEntry(&loadableEntry[T]{NotFound: true})
+	if err != nil {
+		return err
+	}
+
+	options = append(append(append([]store.Option{}, c.Options.NegativeOptions...), options...), store.WithExpiration(c.Options.NegativeTTL))
+
+	return c.Cache.Set(ctx, key, value, options...)
 }
 
 // Delete removes a value from cache
@@ -81,6 +386,11 @@ func (c *LoadableCache[T]) Delete(ctx context.Context, key any) error {
 	return c.Cache.Delete(ctx, key)
 }
 
+// DeleteMany removes values from cache
+func (c *LoadableCache[T]) DeleteMany(ctx context.Context, keys []any) error {
+	return c.Cache.DeleteMany(ctx, keys)
+}
+
 // Invalidate invalidates cache item from given options
 func (c *LoadableCache[T]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
 	return c.Cache.Invalidate(ctx, options...)
@@ -96,9 +406,43 @@ func (c *LoadableCache[T]) GetType() string {
 	return LoadableType
 }
 
+// recordSetError counts the given error of a loaded value set back in cache,
+// if any
+func (c *LoadableCache[T]) recordSetError(err error) {
+	if err == nil {
+		return
+	}
+
+	c.statsMtx.Lock()
+	c.stats.SetErrors++
+	c.statsMtx.Unlock()
+}
+
+// GetStats returns some statistics about the current loadable cache
+func (c *LoadableCache[T]) GetStats() *LoadableStats {
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	stats := *c.stats
+	stats.SetsDropped = int(c.queue.dropped.Load())
+	return &stats
+}
+
+// Close stops setting loaded values back in cache, once the values already
+// queued have been set. Values loaded afterwards are not set back anymore.
 func (c *LoadableCache[T]) Close() error {
-	close(c.SetChannel)
-	c.SetterWg.Wait()
+	c.queue.close()
 
 	return nil
 }
+
+// detachedContext keeps the values of its parent context but is never
+// cancelled, so that a load shared between several callers is not aborted
+// when the caller that started it goes away.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+func (c detachedContext) Value(key any) any         { return c.parent.Value(key) }
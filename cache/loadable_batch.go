@@ -0,0 +1,284 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+)
+
+const (
+	// DefaultBatchWindow is the default time window during which missing keys
+	// are grouped into a single call to the batch load function
+	DefaultBatchWindow = 2 * time.Millisecond
+	// DefaultMaxBatchSize is the default maximum number of keys given to a
+	// single call to the batch load function
+	DefaultMaxBatchSize = 1000
+)
+
+var errBatchNotFound = errors.New("value not found by batch load function")
+
+// BatchLoadFunction loads the values of several keys at once and returns them
+// indexed by their key. Keys missing from the returned map are considered as
+// not found. Given keys have to be comparable.
+type BatchLoadFunction[T any] func(ctx context.Context, keys []any) (map[any]T, error)
+
+// BatchLoadErrors can be returned by a batch load function to report errors
+// for some keys only: other keys are still resolved from the returned values
+type BatchLoadErrors map[any]error
+
+func (e BatchLoadErrors) Error() string {
+	errs := make([]string, 0, len(e))
+	for key, err := range e {
+		errs = append(errs, fmt.Sprintf("%v: %v", key, err))
+	}
+	sort.Strings(errs)
+
+	return fmt.Sprintf("unable to load %d key(s): %s", len(e), strings.Join(errs, ", "))
+}
+
+// NewBatchLoadable instanciates a new cache that uses a batch function to load
+// data: missing keys requested by concurrent calls to Get within the batch
+// window are grouped into a single call to the batch load function
+func NewBatchLoadable[T any](loadFunc BatchLoadFunction[T], cache CacheInterface[T], options ...LoadableOption) *LoadableCache[T] {
+	loadable := NewLoadable[T](nil, cache, append([]LoadableOption{
+		WithBatchWindow(DefaultBatchWindow),
+		WithMaxBatchSize(DefaultMaxBatchSize),
+	}, options...)...)
+
+	loadable.BatchLoadFunc = loadFunc
+	loadable.batcher = &loadableBatcher[T]{
+		loadable: loadable,
+	}
+
+	return loadable
+}
+
+// GetMany returns the objects of the given keys, indexed by key. Objects that
+// are not available in cache are loaded at once using the batch load function,
+// or concurrently using the load function. Keys that are not found are missing
+// from the returned map and errors of other keys are returned as BatchLoadErrors.
+func (c *LoadableCache[T]) GetMany(ctx context.Context, keys []any) (map[any]T, error) {
+	values := make(map[any]T, len(keys))
+	errs := BatchLoadErrors{}
+
+	setResult := func(key any, object T, err error) {
+		if err == nil || errors.Is(err, ErrStaleValue) {
+			values[key] = object
+		}
+		if err != nil && !errors.Is(err, store.NotFound{}) {
+			errs[key] = err
+		}
+	}
+
+	uniqueKeys := make([]any, 0, len(keys))
+	seen := map[string]struct{}{}
+
+	for _, key := range keys {
+		cacheKey := c.getCacheKey(key)
+		if _, ok := seen[cacheKey]; ok {
+			continue
+		}
+		seen[cacheKey] = struct{}{}
+		uniqueKeys = append(uniqueKeys, key)
+	}
+
+	// Errors are handled as cache misses, as done by Get
+	cached, _ := c.Cache.GetMany(ctx, uniqueKeys)
+
+	missingKeys := []any{}
+	for _, key := range uniqueKeys {
+		object, ok := cached[key]
+		if !ok {
+			missingKeys = append(missingKeys, key)
+			continue
+		}
+
+		object, err := c.fromCache(ctx, key, object)
+		setResult(key, object, err)
+	}
+
+	if len(missingKeys) > 0 {
+		if c.BatchLoadFunc != nil {
+			for _, keys := range c.batches(missingKeys) {
+				loaded, err := c.callBatchLoadFunc(detachedContext{ctx}, keys)
+				for _, key := range keys {
+					object, keyErr := loaded.get(key, err)
+					if c.isNegative(keyErr) {
+						c.queue.push(&loadableKeyValue[T]{key: key, notFound: true})
+					} else if keyErr == nil {
+						c.queue.push(&loadableKeyValue[T]{key: key, value: object})
+					}
+					setResult(key, object, keyErr)
+				}
+			}
+		} else {
+			mtx := &sync.Mutex{}
+			wg := &sync.WaitGroup{}
+
+			for _, key := range missingKeys {
+				wg.Add(1)
+				go func(key any) {
+					defer wg.Done()
+
+					object, err := c.load(ctx, key)
+
+					mtx.Lock()
+					defer mtx.Unlock()
+					setResult(key, object, err)
+				}(key)
+			}
+
+			wg.Wait()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return values, err
+	}
+
+	if len(errs) > 0 {
+		return values, errs
+	}
+
+	return values, nil
+}
+
+// batches splits the given keys into batches of the maximum batch size
+func (c *LoadableCache[T]) batches(keys []any) [][]any {
+	maxSize := c.Options.MaxBatchSize
+	if maxSize <= 0 || len(keys) <= maxSize {
+		return [][]any{keys}
+	}
+
+	batches := make([][]any, 0, (len(keys)+maxSize-1)/maxSize)
+	for len(keys) > maxSize {
+		batches = append(batches, keys[:maxSize])
+		keys = keys[maxSize:]
+	}
+
+	return append(batches, keys)
+}
+
+// callBatchLoadFunc calls the batch load function and records its statistics
+func (c *LoadableCache[T]) callBatchLoadFunc(ctx context.Context, keys []any) (batchLoadResult[T], error) {
+	values, err := c.BatchLoadFunc(ctx, keys)
+
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	c.stats.Loads += len(keys)
+	c.stats.BatchLoads++
+
+	result := batchLoadResult[T]{values: values}
+
+	var keyErrs BatchLoadErrors
+	if errors.As(err, &keyErrs) {
+		result.errs = keyErrs
+		err = nil
+	}
+
+	for _, key := range keys {
+		if _, keyErr := result.get(key, err); keyErr != nil && !errors.Is(keyErr, store.NotFound{}) {
+			c.stats.LoadErrors++
+		}
+	}
+
+	return result, err
+}
+
+type batchLoadResult[T any] struct {
+	values map[any]T
+	errs   BatchLoadErrors
+}
+
+// get returns the loaded object of the given key, or its error
+func (r batchLoadResult[T]) get(key any, err error) (T, error) {
+	if err != nil {
+		return *new(T), err
+	}
+
+	if keyErr, ok := r.errs[key]; ok {
+		return *new(T), keyErr
+	}
+
+	if object, ok := r.values[key]; ok {
+		return object, nil
+	}
+
+	return *new(T), store.NotFoundWithCause(errBatchNotFound)
+}
+
+// loadableBatcher groups the keys loaded within the batch window into a
+// single call to the batch load function
+type loadableBatcher[T any] struct {
+	loadable *LoadableCache[T]
+	mtx      sync.Mutex
+	pending  *loadableBatch[T]
+}
+
+type loadableBatch[T any] struct {
+	ctx    context.Context
+	keys   []any
+	done   chan struct{}
+	result batchLoadResult[T]
+	err    error
+}
+
+// load adds the given key to the pending batch and waits for its result
+func (b *loadableBatcher[T]) load(ctx context.Context, key any) (T, error) {
+	b.mtx.Lock()
+
+	batch := b.pending
+	if batch == nil {
+		batch = &loadableBatch[T]{
+			ctx:  detachedContext{ctx},
+			done: make(chan struct{}),
+		}
+		b.pending = batch
+
+		time.AfterFunc(b.loadable.Options.BatchWindow, func() {
+			b.dispatch(batch)
+		})
+	}
+
+	batch.keys = append(batch.keys, key)
+
+	if maxSize := b.loadable.Options.MaxBatchSize; maxSize > 0 && len(batch.keys) >= maxSize {
+		b.pending = nil
+		go b.run(batch)
+	}
+
+	b.mtx.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
+
+	case <-batch.done:
+		return batch.result.get(key, batch.err)
+	}
+}
+
+// dispatch runs the given batch if it is still pending
+func (b *loadableBatcher[T]) dispatch(batch *loadableBatch[T]) {
+	b.mtx.Lock()
+	if b.pending != batch {
+		b.mtx.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mtx.Unlock()
+
+	b.run(batch)
+}
+
+func (b *loadableBatcher[T]) run(batch *loadableBatch[T]) {
+	defer close(batch.done)
+
+	batch.result, batch.err = b.loadable.callBatchLoadFunc(batch.ctx, batch.keys)
+}
@@ -0,0 +1,299 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/prodadidb/gocache/cache"
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewBatchLoadable(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]any, error) {
+		return map[any]any{}, nil
+	}
+
+	// When
+	ch := cache.NewBatchLoadable[any](loadFunc, cache1)
+
+	// Then
+	assert.IsType(t, new(cache.LoadableCache[any]), ch)
+
+	assert.IsType(t, new(cache.BatchLoadFunction[any]), &ch.BatchLoadFunc)
+	assert.Equal(t, cache1, ch.Cache)
+	assert.Equal(t, cache.DefaultBatchWindow, ch.Options.BatchWindow)
+	assert.Equal(t, cache.DefaultMaxBatchSize, ch.Options.MaxBatchSize)
+}
+
+func TestBatchLoadableGetWhenConcurrentMisses(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	mtx := &sync.Mutex{}
+	loadedKeys := [][]any{}
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		mtx.Lock()
+		loadedKeys = append(loadedKeys, keys)
+		mtx.Unlock()
+
+		values := map[any]string{}
+		for _, key := range keys {
+			values[key] = fmt.Sprintf("value-of-%v", key)
+		}
+		return values, nil
+	}
+
+	ch := cache.NewBatchLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithBatchWindow(50*time.Millisecond),
+	)
+
+	// When
+	callers := 20
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			value, err := ch.Get(ctx, key)
+
+			// Then
+			assert.Nil(t, err)
+			assert.Equal(t, "value-of-"+key, value)
+		}(fmt.Sprintf("key-%d", i))
+	}
+	wg.Wait()
+
+	assert.Len(t, loadedKeys, 1)
+	assert.Len(t, loadedKeys[0], callers)
+	assert.Equal(t, 1, ch.GetStats().BatchLoads)
+	assert.Equal(t, callers, ch.GetStats().Loads)
+}
+
+func TestBatchLoadableGetWhenMaxBatchSizeReached(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		values := map[any]string{}
+		for _, key := range keys {
+			values[key] = fmt.Sprintf("value-of-%v", key)
+		}
+		return values, nil
+	}
+
+	ch := cache.NewBatchLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithBatchWindow(time.Hour),
+		cache.WithMaxBatchSize(2),
+	)
+
+	// When
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			value, err := ch.Get(ctx, key)
+
+			// Then
+			assert.Nil(t, err)
+			assert.Equal(t, "value-of-"+key, value)
+		}(fmt.Sprintf("key-%d", i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, ch.GetStats().BatchLoads)
+}
+
+func TestBatchLoadableGetWhenKeyErrors(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	keyErr := errors.New("unable to load key-2")
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		return map[any]string{
+			"key-1": "value-1",
+		}, cache.BatchLoadErrors{"key-2": keyErr}
+	}
+
+	ch := cache.NewBatchLoadable[string](loadFunc, cache.New[string](gocacheStore))
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Equal(t, map[any]string{"key-1": "value-1"}, values)
+	assert.Equal(t, cache.BatchLoadErrors{"key-2": keyErr}, err)
+
+	_, err = ch.Get(ctx, "key-3")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+}
+
+func TestBatchLoadableGetWhenBatchError(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadErr := errors.New("an error has occurred while loading data from custom source")
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		return nil, loadErr
+	}
+
+	ch := cache.NewBatchLoadable[string](loadFunc, cache.New[string](gocacheStore))
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Equal(t, "", value)
+	assert.Equal(t, loadErr, err)
+	assert.Equal(t, 1, ch.GetStats().LoadErrors)
+}
+
+func TestLoadableGetManyWhenBatchLoadFunc(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadedKeys := [][]any{}
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		loadedKeys = append(loadedKeys, keys)
+
+		values := map[any]string{}
+		for _, key := range keys {
+			if key != "key-4" {
+				values[key] = fmt.Sprintf("value-of-%v", key)
+			}
+		}
+		return values, nil
+	}
+
+	ch := cache.NewBatchLoadable[string](loadFunc, cache.New[string](gocacheStore))
+
+	err := ch.Set(ctx, "key-1", "value-in-cache")
+	assert.Nil(t, err)
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2", "key-3", "key-4", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]string{
+		"key-1": "value-in-cache",
+		"key-2": "value-of-key-2",
+		"key-3": "value-of-key-3",
+	}, values)
+
+	assert.Len(t, loadedKeys, 1)
+	assert.Equal(t, []any{"key-2", "key-3", "key-4"}, loadedKeys[0])
+
+	assert.Eventually(t, func() bool {
+		value, err := gocacheStore.Get(ctx, "key-3")
+		return err == nil && value == "value-of-key-3"
+	}, time.Second, time.Millisecond)
+}
+
+func TestLoadableGetManyWhenMaxBatchSizeReached(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadedKeys := [][]any{}
+
+	loadFunc := func(_ context.Context, keys []any) (map[any]string, error) {
+		loadedKeys = append(loadedKeys, keys)
+
+		values := map[any]string{}
+		for _, key := range keys {
+			values[key] = fmt.Sprintf("value-of-%v", key)
+		}
+		return values, nil
+	}
+
+	ch := cache.NewBatchLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithMaxBatchSize(2),
+	)
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2", "key-3", "key-4", "key-5"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Len(t, values, 5)
+	assert.Equal(t, "value-of-key-5", values["key-5"])
+
+	assert.Equal(t, [][]any{{"key-1", "key-2"}, {"key-3", "key-4"}, {"key-5"}}, loadedKeys)
+	assert.Equal(t, 3, ch.GetStats().BatchLoads)
+}
+
+func TestLoadableGetManyWhenLoadFunc(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	mtx := &sync.Mutex{}
+	loadedKeys := []string{}
+
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		mtx.Lock()
+		loadedKeys = append(loadedKeys, key.(string))
+		mtx.Unlock()
+
+		return fmt.Sprintf("value-of-%v", key), nil
+	}
+
+	ch := cache.NewLoadable[string](loadFunc, cache.New[string](gocacheStore))
+
+	// When
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]string{
+		"key-1": "value-of-key-1",
+		"key-2": "value-of-key-2",
+	}, values)
+
+	sort.Strings(loadedKeys)
+	assert.Equal(t, []string{"key-1", "key-2"}, loadedKeys)
+}
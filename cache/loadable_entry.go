@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// loadableEntryHeader prefixes []byte and string values written by a
+// LoadableCache along with their metadata.
+var loadableEntryHeader = []byte("\x00gcl\x01")
+
+const (
+	loadableEntryHeaderSize = 1 + 8 + 8 // flags, soft deadline, hard deadline
+
+	// loadableEntryFlagNotFound marks a negative entry
+	loadableEntryFlagNotFound byte = 0x01
+)
+
+// ErrLoadableEntryNotSupported is returned when a LoadableCache needs to store
+// metadata along with a value whose type cannot hold them.
+var ErrLoadableEntryNotSupported = errors.New("loadable cache entries require a []byte, string or interface value type")
+
+// loadableEntry is the envelope written by a LoadableCache when it needs to
+// keep per-entry metadata next to the value, so that they are available with
+// every store, even the ones that do not return a TTL.
+type loadableEntry[T any] struct {
+	Value        T
+	SoftDeadline time.Time
+	HardDeadline time.Time
+	NotFound     bool
+}
+
+// encodeLoadableEntry returns the given entry as a value of type T: []byte and
+// string values (including the ones held by an interface type, and negative
+// entries) are prefixed by a binary header, so that any store can keep them. Other values are wrapped
+// in the entry when T is an interface type, which only stores keeping Go
+// values support.
+func encodeLoadableEntry[T any](entry *loadableEntry[T]) (T, error) {
+	switch v := any(entry.Value).(type) {
+	case []byte:
+		if result, ok := any(encodeLoadableEntryBytes(entry, v)).(T); ok {
+			return result, nil
+		}
+	case string:
+		if result, ok := any(string(encodeLoadableEntryBytes(entry, []byte(v)))).(T); ok {
+			return result, nil
+		}
+	}
+
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() == reflect.Interface {
+		// Negative entries hold no value, so they are written as bytes
+		if entry.NotFound {
+			return any(encodeLoadableEntryBytes(entry, nil)).(T), nil
+		}
+		return any(entry).(T), nil
+	}
+
+	return *new(T), ErrLoadableEntryNotSupported
+}
+
+func encodeLoadableEntryBytes[T any](entry *loadableEntry[T], value []byte) []byte {
+	result := make([]byte, 0, len(loadableEntryHeader)+loadableEntryHeaderSize+len(value))
+	result = append(result, loadableEntryHeader...)
+
+	var flags byte
+	if entry.NotFound {
+		flags |= loadableEntryFlagNotFound
+	}
+	result = append(result, flags)
+	result = binary.BigEndian.AppendUint64(result, uint64(unixNano(entry.SoftDeadline)))
+	result = binary.BigEndian.AppendUint64(result, uint64(unixNano(entry.HardDeadline)))
+
+	return append(result, value...)
+}
+
+// decodeLoadableEntry returns the entry stored in the given value, or false
+// when the value has not been written as an entry.
+func decodeLoadableEntry[T any](value T) (*loadableEntry[T], bool) {
+	switch v := any(value).(type) {
+	case *loadableEntry[T]:
+		return v, true
+
+	case []byte:
+		entry, payload, ok := decodeLoadableEntryBytes[T](v)
+		if !ok {
+			return nil, false
+		}
+		entry.Value, ok = any(payload).(T)
+		return entry, ok
+
+	case string:
+		entry, payload, ok := decodeLoadableEntryBytes[T]([]byte(v))
+		if !ok {
+			return nil, false
+		}
+		entry.Value, ok = any(string(payload)).(T)
+		return entry, ok
+	}
+
+	return nil, false
+}
+
+func decodeLoadableEntryBytes[T any](value []byte) (*loadableEntry[T], []byte, bool) {
+	if !bytes.HasPrefix(value, loadableEntryHeader) || len(value) < len(loadableEntryHeader)+loadableEntryHeaderSize {
+		return nil, nil, false
+	}
+
+	header := value[len(loadableEntryHeader):]
+
+	entry := &loadableEntry[T]{
+		SoftDeadline: fromUnixNano(int64(binary.BigEndian.Uint64(header[1:9]))),
+		HardDeadline: fromUnixNano(int64(binary.BigEndian.Uint64(header[9:17]))),
+		NotFound:     header[0]&loadableEntryFlagNotFound != 0,
+	}
+
+	return entry, header[loadableEntryHeaderSize:], true
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(nsec int64) time.Time {
+	if nsec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nsec)
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+)
+
+// LoadableOption represents a loadable cache option function.
+type LoadableOption func(o *LoadableOptions)
+
+type LoadableOptions struct {
+	Expiration   time.Duration
+	SoftTTL      time.Duration
+	RefreshAhead time.Duration
+	MaxStaleness time.Duration
+
+	NegativeTTL     time.Duration
+	NegativeOptions []store.Option
+
+	BatchWindow  time.Duration
+	MaxBatchSize int
+
+	Queue QueueOptions
+}
+
+// usesEntries returns true when values have to be written along with their
+// metadata (see loadableEntry)
+func (o *LoadableOptions) usesEntries() bool {
+	return o.SoftTTL > 0 || o.RefreshAhead > 0 || o.MaxStaleness > 0
+}
+
+func applyLoadableOptions(opts ...LoadableOption) *LoadableOptions {
+	o := &LoadableOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithLoadExpiration allows to specify the expiration time used when a value
+// returned by the load function is set back in cache.
+func WithLoadExpiration(expiration time.Duration) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.Expiration = expiration
+	}
+}
+
+// WithSoftTTL allows to specify a soft time to live: once an entry is older
+// than this duration, it is still returned by Get but a background refresh is
+// triggered to load a fresh value.
+// Like WithRefreshAhead and WithMaxStaleness, it stores metadata along with
+// the values, which requires []byte or string values: other values can only
+// be set in a cache typed with an interface whose store keeps Go values (the
+// memory store or go-cache for instance), and fail otherwise (see
+// NewLoadable).
+func WithSoftTTL(ttl time.Duration) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.SoftTTL = ttl
+	}
+}
+
+// WithRefreshAhead allows to specify a window before the expiration of an
+// entry during which Get triggers a background refresh of the value.
+// The expiration of an entry is only known when it has been set with an
+// expiration, either using WithLoadExpiration or store.WithExpiration.
+// It requires a []byte, string or interface value type (see WithSoftTTL).
+func WithRefreshAhead(window time.Duration) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.RefreshAhead = window
+	}
+}
+
+// WithMaxStaleness allows to keep entries in cache during the given grace
+// period after their expiration: when the load function fails to reload an
+// expired entry during this period, the stale value is returned along with an
+// error wrapping ErrStaleValue.
+// The expiration of an entry is only known when it has been set with an
+// expiration, either using WithLoadExpiration or store.WithExpiration.
+// It requires a []byte, string or interface value type (see WithSoftTTL).
+func WithMaxStaleness(maxStaleness time.Duration) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.MaxStaleness = maxStaleness
+	}
+}
+
+// WithNegativeCaching allows to cache the absence of a value when the load
+// function returns a store.NotFound error: during the given time to live, Get
+// returns a store.NotFound error without calling the load function.
+// Given store options (tags for instance) are used when setting the entry.
+// Negative entries are written as []byte values in a cache typed with an
+// interface, and require a []byte or string value type otherwise.
+func WithNegativeCaching(ttl time.Duration, options ...store.Option) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.NegativeTTL = ttl
+		o.NegativeOptions = options
+	}
+}
+
+// WithBatchWindow allows to specify the time window during which keys missing
+// from cache are grouped into a single call to the batch load function.
+func WithBatchWindow(window time.Duration) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.BatchWindow = window
+	}
+}
+
+// WithMaxBatchSize allows to specify the maximum number of keys given to a
+// single call to the batch load function: a batch is loaded as soon as it
+// reaches this size, and the keys missing from cache in GetMany are loaded in
+// batches of this size. A zero value means no limit.
+func WithMaxBatchSize(size int) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.MaxBatchSize = size
+	}
+}
+
+// WithSetQueue allows to specify the size of the queue of values returned by
+// the load function and set back in cache, the number of goroutines setting
+// them, and whether values are dropped when the queue is full or set
+// synchronously.
+func WithSetQueue(options QueueOptions) LoadableOption {
+	return func(o *LoadableOptions) {
+		o.Queue = options
+	}
+}
@@ -1,11 +1,15 @@
 package cache_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/allegro/bigcache/v3"
 	gocache "github.com/patrickmn/go-cache"
 	"github.com/prodadidb/gocache/cache"
 	"github.com/prodadidb/gocache/store"
@@ -33,6 +37,27 @@ func TestNewLoadable(t *testing.T) {
 	assert.Equal(t, cache1, ch.Cache)
 }
 
+func TestNewLoadableWithOptions(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+
+	loadFunc := func(_ context.Context, key any) (any, []store.Option, error) {
+		return "test data loaded", []store.Option{store.WithExpiration(5 * time.Second)}, nil
+	}
+
+	// When
+	ch := cache.NewLoadableWithOptions[any](loadFunc, cache1)
+
+	// Then
+	assert.IsType(t, new(cache.LoadableCache[any]), ch)
+
+	assert.IsType(t, new(cache.LoadFunctionWithOptions[any]), &ch.LoadFuncWithOptions)
+	assert.Nil(t, ch.LoadFunc)
+	assert.Equal(t, cache1, ch.Cache)
+}
+
 func TestLoadableGetWhenAlreadyInCache(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -122,6 +147,152 @@ func TestLoadableGetWhenAvailableInLoadFunc(t *testing.T) {
 	assert.Equal(t, cacheValue, value)
 }
 
+func TestLoadableGetWhenAvailableInLoadFuncWithOptions(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := &struct {
+		Hello string
+	}{
+		Hello: "world",
+	}
+
+	set := make(chan struct{})
+
+	// Cache 1
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(nil, errors.New("unable to find in cache 1"))
+	cache1.EXPECT().Set(ctx, "my-key", cacheValue, store.OptionsMatcher{
+		Expiration: 10 * time.Second,
+		Tags:       []string{"category-1"},
+	}).DoAndReturn(func(_ context.Context, _ any, _ any, _ ...store.Option) error {
+		close(set)
+		return nil
+	})
+
+	loadFunc := func(_ context.Context, key any) (any, []store.Option, error) {
+		return cacheValue, []store.Option{
+			store.WithExpiration(10 * time.Second),
+			store.WithTags([]string{"category-1"}),
+		}, nil
+	}
+
+	ch := cache.NewLoadableWithOptions[any](loadFunc, cache1, cache.WithLoadExpiration(5*time.Second))
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+
+	select {
+	case <-set:
+	case <-time.After(time.Second):
+		t.Fatal("loaded value has not been set back in cache")
+	}
+}
+
+func TestLoadableGetWhenConcurrentMissesOnSameKey(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := "my-value"
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").AnyTimes().Return(nil, errors.New("unable to find in cache 1"))
+	cache1.EXPECT().Set(ctx, "my-key", cacheValue).AnyTimes().Return(nil)
+
+	var calls int32
+	release := make(chan struct{})
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return cacheValue, nil
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache1)
+
+	// When
+	callers := 10
+	results := make(chan any, callers)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := ch.Get(ctx, "my-key")
+			assert.Nil(t, err)
+			results <- value
+		}()
+	}
+
+	// Wait for all callers to wait on the shared load
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(1 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	wg.Wait()
+	close(results)
+
+	// Then
+	for value := range results {
+		assert.Equal(t, cacheValue, value)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, ch.GetStats().Loads)
+	assert.Equal(t, callers-1, ch.GetStats().LoadDeduplicated)
+}
+
+func TestLoadableGetWhenContextCanceledWhileLoading(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cacheValue := "my-value"
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(nil, errors.New("unable to find in cache 1"))
+	cache1.EXPECT().Set(context.Background(), "my-key", cacheValue).AnyTimes().Return(nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loadErr := make(chan error, 1)
+	loadFunc := func(loadCtx context.Context, key any) (any, error) {
+		close(started)
+		<-release
+		loadErr <- loadCtx.Err()
+		return cacheValue, nil
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache1)
+
+	// When
+	getErr := make(chan error)
+	go func() {
+		_, err := ch.Get(ctx, "my-key")
+		getErr <- err
+	}()
+
+	<-started
+	cancel()
+
+	// Then
+	assert.Equal(t, context.Canceled, <-getErr)
+
+	close(release)
+	assert.Nil(t, <-loadErr)
+}
+
 func TestLoadableDelete(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -299,3 +470,541 @@ func TestLoadableGocache(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, cacheValue, value)
 }
+
+func TestLoadableGetWhenSoftTTLExpired(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return "first-value", nil
+		}
+		return "second-value", nil
+	}
+
+	ch := cache.NewLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithSoftTTL(50*time.Millisecond),
+	)
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "first-value", value)
+
+	assert.Eventually(t, func() bool {
+		value, err := ch.Get(ctx, "my-key")
+		return err == nil && value == "first-value"
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// When
+	value, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "first-value", value)
+
+	assert.Eventually(t, func() bool {
+		value, err := ch.Get(ctx, "my-key")
+		return err == nil && value == "second-value"
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, ch.GetStats().Refreshes)
+}
+
+func TestLoadableGetWhenInRefreshAheadWindow(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	bigcacheClient, _ := bigcache.New(ctx, bigcache.DefaultConfig(5*time.Minute))
+	bigcacheStore := store.NewBigcache(bigcacheClient)
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return []byte("my-value"), nil
+	}
+
+	ch := cache.NewLoadable[[]byte](
+		loadFunc,
+		cache.New[[]byte](bigcacheStore),
+		cache.WithLoadExpiration(time.Second),
+		cache.WithRefreshAhead(900*time.Millisecond),
+	)
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("my-value"), value)
+
+	assert.Eventually(t, func() bool {
+		value, err := bigcacheStore.Get(ctx, "my-key")
+		return err == nil && len(value.([]byte)) > len("my-value")
+	}, time.Second, time.Millisecond)
+
+	// Refresh-ahead window is not reached yet
+	value, err = ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("my-value"), value)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	time.Sleep(150 * time.Millisecond)
+
+	// When
+	value, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("my-value"), value)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestLoadableGetWhenSoftTTLAndValueTypeNotSupported(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	type Book struct {
+		Name string
+	}
+
+	cache1 := NewMockSetterCacheInterface[Book](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(Book{}, store.NotFound{})
+
+	loadFunc := func(_ context.Context, key any) (Book, error) {
+		return Book{Name: "my-book"}, nil
+	}
+
+	ch := cache.NewLoadable[Book](
+		loadFunc,
+		cache1,
+		cache.WithSoftTTL(time.Second),
+		cache.WithSetQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, Book{Name: "my-book"}, value)
+	assert.Equal(t, 1, ch.GetStats().SetErrors)
+
+	err = ch.Set(ctx, "my-key", Book{Name: "my-book"})
+	assert.ErrorIs(t, err, cache.ErrLoadableEntryNotSupported)
+}
+
+func TestLoadableSetWhenSoftTTLAndInterfaceTypeHoldingBytes(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return nil, errors.New("should not be called")
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache.New[any](memoryStore), cache.WithSoftTTL(time.Minute))
+
+	// When
+	err := ch.Set(ctx, "my-key", []byte("my-value"))
+
+	// Then
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.IsType(t, []byte{}, stored)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gcl\x01")))
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("my-value"), value)
+}
+
+func TestLoadableGetWhenNegativeCachingAndValueTypeNotSupported(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	type Book struct {
+		Name string
+	}
+
+	cache1 := NewMockSetterCacheInterface[Book](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(Book{}, store.NotFound{})
+
+	loadFunc := func(_ context.Context, key any) (Book, error) {
+		return Book{}, store.NotFound{}
+	}
+
+	ch := cache.NewLoadable[Book](
+		loadFunc,
+		cache1,
+		cache.WithNegativeCaching(time.Second),
+		cache.WithSetQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	// When
+	_, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Equal(t, 1, ch.GetStats().SetErrors)
+}
+
+func TestLoadableGetWhenNegativeCachingAndInterfaceType(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, store.NotFound{}
+	}
+
+	ch := cache.NewLoadable[any](
+		loadFunc,
+		cache.New[any](memoryStore),
+		cache.WithNegativeCaching(time.Minute),
+		cache.WithSetQueue(cache.QueueOptions{Sync: true}),
+	)
+
+	_, err := ch.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	// When
+	_, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, ch.GetStats().NegativeHits)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.IsType(t, []byte{}, stored)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gcl\x01")))
+}
+
+func TestLoadableGetWhenExpiredAndLoadFuncFails(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadErr := errors.New("an error has occurred while loading data from custom source")
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return "my-value", nil
+		}
+		return "", loadErr
+	}
+
+	ch := cache.NewLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithLoadExpiration(50*time.Millisecond),
+		cache.WithMaxStaleness(5*time.Second),
+	)
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	assert.Eventually(t, func() bool {
+		_, err := gocacheStore.Get(ctx, "my-key")
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// When
+	value, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Equal(t, "my-value", value)
+	assert.True(t, errors.Is(err, cache.ErrStaleValue))
+	assert.True(t, errors.Is(err, loadErr))
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, ch.GetStats().StaleServes)
+}
+
+func TestLoadableGetWhenExpiredAndLoadFuncSucceeds(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return "first-value", nil
+		}
+		return "second-value", nil
+	}
+
+	ch := cache.NewLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithLoadExpiration(50*time.Millisecond),
+		cache.WithMaxStaleness(5*time.Second),
+	)
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "first-value", value)
+
+	assert.Eventually(t, func() bool {
+		_, err := gocacheStore.Get(ctx, "my-key")
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(60 * time.Millisecond)
+
+	// When
+	value, err = ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "second-value", value)
+	assert.Equal(t, 0, ch.GetStats().StaleServes)
+}
+
+func TestLoadableGetWhenNegativeCaching(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	var calls int32
+	loadFunc := func(_ context.Context, key any) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, store.NotFoundWithCause(errors.New("record does not exist"))
+	}
+
+	ch := cache.NewLoadable[[]byte](
+		loadFunc,
+		cache.New[[]byte](gocacheStore),
+		cache.WithNegativeCaching(5*time.Second, store.WithTags([]string{"not-found"})),
+	)
+
+	_, err := ch.Get(ctx, "my-key")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	assert.Eventually(t, func() bool {
+		_, err := gocacheStore.Get(ctx, "my-key")
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, ch.GetStats().NegativeHits)
+
+	// Negative entry is taggable
+	err = ch.Invalidate(ctx, store.WithInvalidateTags([]string{"not-found"}))
+	assert.Nil(t, err)
+
+	_, err = gocacheStore.Get(ctx, "my-key")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+}
+
+func TestLoadableGetWhenNegativeEntryOverriddenBySet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		return "", store.NotFoundWithCause(errors.New("record does not exist"))
+	}
+
+	ch := cache.NewLoadable[string](
+		loadFunc,
+		cache.New[string](gocacheStore),
+		cache.WithNegativeCaching(5*time.Second),
+	)
+
+	_, err := ch.Get(ctx, "my-key")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	assert.Eventually(t, func() bool {
+		_, err := gocacheStore.Get(ctx, "my-key")
+		return err == nil
+	}, time.Second, time.Millisecond)
+
+	// When
+	err = ch.Set(ctx, "my-key", "my-value")
+
+	// Then
+	assert.Nil(t, err)
+
+	value, err := ch.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestLoadableSetManyWhenSoftTTL(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	gocacheClient := gocache.New(5*time.Second, 5*time.Second)
+	gocacheStore := store.NewGoCache(gocacheClient)
+
+	loadFunc := func(_ context.Context, key any) (string, error) {
+		return "", errors.New("load function should not be called")
+	}
+
+	ch := cache.NewLoadable[string](loadFunc, cache.New[string](gocacheStore), cache.WithSoftTTL(time.Minute))
+
+	// When
+	err := ch.SetMany(ctx, map[any]string{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	})
+
+	// Then
+	assert.Nil(t, err)
+
+	value, err := gocacheStore.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	assert.NotEqual(t, "value-1", value)
+
+	values, err := ch.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]string{"key-1": "value-1", "key-2": "value-2"}, values)
+
+	err = ch.DeleteMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+
+	_, err = gocacheStore.Get(ctx, "key-1")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestLoadableGetWhenSyncSet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return "my-value", nil
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache.New[any](memoryStore), cache.WithSetQueue(cache.QueueOptions{Sync: true}))
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+	assert.Nil(t, ch.SetChannel)
+
+	value, err = memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestLoadableGetWhenSetQueueFull(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Get(ctx, gomock.Any()).Return(nil, errors.New("unable to find in cache 1")).Times(3)
+	cache1.EXPECT().Set(gomock.Any(), gomock.Any(), "my-value").
+		DoAndReturn(func(_ context.Context, _ any, _ any, _ ...store.Option) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}).Times(2)
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return "my-value", nil
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache1, cache.WithSetQueue(cache.QueueOptions{Size: 1, DropOnFull: true}))
+
+	// When
+	_, err := ch.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	<-started
+
+	_, err = ch.Get(ctx, "key-2")
+	assert.Nil(t, err)
+
+	_, err = ch.Get(ctx, "key-3")
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, 1, ch.GetStats().SetsDropped)
+
+	close(release)
+	assert.Nil(t, ch.Close())
+}
+
+func TestLoadableClose(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return "my-value", nil
+	}
+
+	ch := cache.NewLoadable[any](loadFunc, cache.New[any](memoryStore))
+
+	_, err := ch.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	// When
+	err = ch.Close()
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = memoryStore.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	value, err := ch.Get(ctx, "key-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	_, err = memoryStore.Get(ctx, "key-2")
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Equal(t, 1, ch.GetStats().SetsDropped)
+
+	assert.Nil(t, ch.Close())
+}
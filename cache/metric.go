@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/prodadidb/gocache/metrics"
 	"github.com/prodadidb/gocache/store"
@@ -35,16 +36,35 @@ func (c *MetricCache[T]) Get(ctx context.Context, key any) (T, error) {
 	return result, err
 }
 
+// GetMany obtains values from cache and also records metrics
+func (c *MetricCache[T]) GetMany(ctx context.Context, keys []any) (map[any]T, error) {
+	result, err := c.Cache.GetMany(ctx, keys)
+
+	c.updateMetrics(c.Cache)
+
+	return result, err
+}
+
 // Set sets a value from the cache
 func (c *MetricCache[T]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
 	return c.Cache.Set(ctx, key, object, options...)
 }
 
+// SetMany sets values in the cache
+func (c *MetricCache[T]) SetMany(ctx context.Context, items map[any]T, options ...store.Option) error {
+	return c.Cache.SetMany(ctx, items, options...)
+}
+
 // Delete removes a value from the cache
 func (c *MetricCache[T]) Delete(ctx context.Context, key any) error {
 	return c.Cache.Delete(ctx, key)
 }
 
+// DeleteMany removes values from the cache
+func (c *MetricCache[T]) DeleteMany(ctx context.Context, keys []any) error {
+	return c.Cache.DeleteMany(ctx, keys)
+}
+
 // Invalidate invalidates cache item from given options
 func (c *MetricCache[T]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
 	return c.Cache.Invalidate(ctx, options...)
@@ -57,14 +77,46 @@ func (c *MetricCache[T]) Clear(ctx context.Context) error {
 
 // Get obtains a value from cache and also records metrics
 func (c *MetricCache[T]) updateMetrics(cache CacheInterface[T]) {
+	// Cache statistics are only recorded by providers implementing Recorder
+	recorder, canRecord := c.Metrics.(metrics.Recorder)
+
 	switch current := cache.(type) {
 	case *ChainCache[T]:
+		if canRecord {
+			stats := current.GetStats()
+			for layer, hits := range stats.LayerHits {
+				recorder.Record(ChainType, fmt.Sprintf("layer_%d_hit_count", layer), float64(hits))
+			}
+			recorder.Record(ChainType, "miss_count", float64(stats.Misses))
+			recorder.Record(ChainType, "hedged_read_count", float64(stats.HedgedReads))
+			recorder.Record(ChainType, "backfill_rejected_count", float64(stats.BackfillsRejected))
+			recorder.Record(ChainType, "dropped_count", float64(current.Dropped()))
+		}
+
 		for _, cache := range current.GetCaches() {
 			c.updateMetrics(cache)
 		}
 
+	case *LoadableCache[T]:
+		if canRecord {
+			stats := current.GetStats()
+			recorder.Record(LoadableType, "load_count", float64(stats.Loads))
+			recorder.Record(LoadableType, "load_error", float64(stats.LoadErrors))
+			recorder.Record(LoadableType, "load_deduplicated", float64(stats.LoadDeduplicated))
+			recorder.Record(LoadableType, "refresh_count", float64(stats.Refreshes))
+			recorder.Record(LoadableType, "stale_serve_count", float64(stats.StaleServes))
+			recorder.Record(LoadableType, "negative_hit_count", float64(stats.NegativeHits))
+			recorder.Record(LoadableType, "batch_load_count", float64(stats.BatchLoads))
+			recorder.Record(LoadableType, "set_dropped_count", float64(stats.SetsDropped))
+			recorder.Record(LoadableType, "set_error_count", float64(stats.SetErrors))
+		}
+
+		c.updateMetrics(current.Cache)
+
 	case SetterCacheInterface[T]:
-		c.Metrics.RecordFromCodec(current.GetCodec())
+		if codec := current.GetCodec(); codec != nil {
+			c.Metrics.RecordFromCodec(codec)
+		}
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/prodadidb/gocache/cache"
+	"github.com/prodadidb/gocache/store"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -15,6 +16,12 @@ import (
 //go:generate mockgen -destination=mock_metrics_interface_test.go -package=cache_test -source=../metrics/interface.go
 //go:generate mockgen -destination=mock_store_interface_test.go -package=cache_test -source=../store/interface.go
 
+// recordingMetrics is a metrics provider implementing metrics.Recorder
+type recordingMetrics struct {
+	*MockMetricsInterface
+	*MockRecorder
+}
+
 func TestNewMetric(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -88,18 +95,168 @@ func TestMetricGetWhenChainCache(t *testing.T) {
 	chainCache := cache.NewChain[any](cache1)
 
 	metrics := NewMockMetricsInterface(ctrl)
+	recorder := NewMockRecorder(ctrl)
+	recorder.EXPECT().Record(cache.ChainType, "layer_0_hit_count", float64(1))
+	recorder.EXPECT().Record(cache.ChainType, "miss_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "hedged_read_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "backfill_rejected_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "dropped_count", float64(0))
 	metrics.EXPECT().RecordFromCodec(codec1).AnyTimes()
 
+	ch := cache.NewMetric[any](&recordingMetrics{metrics, recorder}, chainCache)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestMetricGetWhenChainCacheAndProviderNotRecorder(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	codec1 := NewMockCodecInterface(ctrl)
+
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().GetWithTTL(ctx, "my-key").Return("my-value", 0*time.Second, nil)
+	cache1.EXPECT().GetCodec().Return(codec1)
+
+	chainCache := cache.NewChain[any](cache1)
+
+	// Only the statistics of the codec are recorded
+	metrics := NewMockMetricsInterface(ctrl)
+	metrics.EXPECT().RecordFromCodec(codec1)
+
 	ch := cache.NewMetric[any](metrics, chainCache)
 
 	// When
 	value, err := ch.Get(ctx, "my-key")
 
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestMetricGetWhenLoadableCache(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := &struct {
+		Hello string
+	}{
+		Hello: "world",
+	}
+
+	codec1 := NewMockCodecInterface(ctrl)
+	cache1 := NewMockSetterCacheInterface[any](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(cacheValue, nil)
+	cache1.EXPECT().GetCodec().Return(codec1)
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return nil, errors.New("should not be called")
+	}
+
+	loadable := cache.NewLoadable[any](loadFunc, cache1)
+
+	metrics := NewMockMetricsInterface(ctrl)
+	recorder := NewMockRecorder(ctrl)
+	recorder.EXPECT().Record(cache.LoadableType, "load_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "load_error", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "load_deduplicated", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "refresh_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "stale_serve_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "negative_hit_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "batch_load_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "set_dropped_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "set_error_count", float64(0))
+	metrics.EXPECT().RecordFromCodec(codec1)
+
+	ch := cache.NewMetric[any](&recordingMetrics{metrics, recorder}, loadable)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
 	// Then
 	assert.Nil(t, err)
 	assert.Equal(t, cacheValue, value)
 }
 
+func TestMetricGetWhenChainCacheDropped(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	store1 := store.NewMemory()
+	store2 := store.NewMemory()
+
+	_ = store2.Set(ctx, "my-key", "my-value")
+
+	chainCache := cache.NewChain[any](cache.New[any](store1), cache.New[any](store2))
+	assert.Nil(t, chainCache.Close())
+
+	metrics := NewMockMetricsInterface(ctrl)
+	recorder := NewMockRecorder(ctrl)
+	recorder.EXPECT().Record(cache.ChainType, "layer_0_hit_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "layer_1_hit_count", float64(1))
+	recorder.EXPECT().Record(cache.ChainType, "miss_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "hedged_read_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "backfill_rejected_count", float64(0))
+	recorder.EXPECT().Record(cache.ChainType, "dropped_count", float64(1))
+	metrics.EXPECT().RecordFromCodec(gomock.Any()).Times(2)
+
+	ch := cache.NewMetric[any](&recordingMetrics{metrics, recorder}, chainCache)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestMetricGetWhenLoadableCacheSetDropped(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	loadFunc := func(_ context.Context, key any) (any, error) {
+		return "my-value", nil
+	}
+
+	loadable := cache.NewLoadable[any](loadFunc, cache.New[any](store.NewMemory()))
+	assert.Nil(t, loadable.Close())
+
+	metrics := NewMockMetricsInterface(ctrl)
+	recorder := NewMockRecorder(ctrl)
+	recorder.EXPECT().Record(cache.LoadableType, "load_count", float64(1))
+	recorder.EXPECT().Record(cache.LoadableType, "load_error", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "load_deduplicated", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "refresh_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "stale_serve_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "negative_hit_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "batch_load_count", float64(0))
+	recorder.EXPECT().Record(cache.LoadableType, "set_dropped_count", float64(1))
+	recorder.EXPECT().Record(cache.LoadableType, "set_error_count", float64(0))
+	metrics.EXPECT().RecordFromCodec(gomock.Any())
+
+	ch := cache.NewMetric[any](&recordingMetrics{metrics, recorder}, loadable)
+
+	// When
+	value, err := ch.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
 func TestMetricSet(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+)
+
+const (
+	// NamespaceGenerationKeyPattern represents the pattern of the key holding
+	// the generation of a namespace in the store
+	NamespaceGenerationKeyPattern = "gocache_ns_generation_%s"
+)
+
+// CacheNamespaceProvider is implemented by keys belonging to a versioned
+// namespace
+type CacheNamespaceProvider interface {
+	GetCacheNamespace() string
+}
+
+// NamespacedKey is a key belonging to a versioned namespace, for instance all
+// the values of a tenant
+type NamespacedKey struct {
+	Namespace string
+	Key       any
+}
+
+// InNamespace returns the given key in the given versioned namespace.
+// The generation of a namespace is stored without expiration: when the store
+// evicts it (once full for instance), a new generation is written, which
+// invalidates all the values of the namespace.
+func InNamespace(namespace string, key any) NamespacedKey {
+	return NamespacedKey{
+		Namespace: namespace,
+		Key:       key,
+	}
+}
+
+func (k NamespacedKey) GetCacheNamespace() string {
+	return k.Namespace
+}
+
+func (k NamespacedKey) GetCacheKey() string {
+	return k.Namespace + ":" + getCacheKey(k.Key)
+}
+
+// NamespaceInvalidator is implemented by caches supporting versioned
+// namespaces
+type NamespaceInvalidator interface {
+	InvalidateNamespace(ctx context.Context, namespace string) error
+}
+
+// namespaceGeneration is a generation of a namespace kept locally
+type namespaceGeneration struct {
+	value     string
+	expiresAt time.Time
+}
+
+// namespaceGenerations keeps the generations of namespaces read from a store
+type namespaceGenerations struct {
+	mtx         sync.Mutex
+	generations map[string]namespaceGeneration
+}
+
+// get returns the current generation of the given namespace, reading it from
+// the given store (and initializing it when missing) once the local one has
+// expired
+func (g *namespaceGenerations) get(ctx context.Context, s store.StoreInterface, namespace string, ttl time.Duration) (string, error) {
+	g.mtx.Lock()
+	generation, ok := g.generations[namespace]
+	g.mtx.Unlock()
+
+	if ok && time.Now().Before(generation.expiresAt) {
+		return generation.value, nil
+	}
+
+	value, err := s.Get(ctx, fmt.Sprintf(NamespaceGenerationKeyPattern, namespace))
+	switch {
+	case err == nil:
+		generation.value = generationValue(value)
+	case errors.Is(err, store.NotFound{}):
+		return g.initialize(ctx, s, namespace, ttl)
+	default:
+		return "", err
+	}
+
+	if generation.value == "" {
+		return g.initialize(ctx, s, namespace, ttl)
+	}
+
+	g.set(namespace, generation.value, ttl)
+
+	return generation.value, nil
+}
+
+// initialize writes a first generation of the given namespace in the given
+// store and reads it back, so that instances initializing the namespace
+// concurrently use the generation written last rather than their own one
+func (g *namespaceGenerations) initialize(ctx context.Context, s store.StoreInterface, namespace string, ttl time.Duration) (string, error) {
+	key := fmt.Sprintf(NamespaceGenerationKeyPattern, namespace)
+	generation := newGeneration()
+
+	err := s.Set(ctx, key, []byte(generation), store.WithExpiration(0))
+	if err != nil {
+		return "", err
+	}
+
+	value, err := s.Get(ctx, key)
+	switch {
+	case err == nil:
+		if stored := generationValue(value); stored != "" {
+			generation = stored
+		}
+	case !errors.Is(err, store.NotFound{}):
+		return "", err
+	}
+
+	g.set(namespace, generation, ttl)
+
+	return generation, nil
+}
+
+// bump writes a new generation of the given namespace in the given store,
+// making the values of the previous generations unreachable
+func (g *namespaceGenerations) bump(ctx context.Context, s store.StoreInterface, namespace string, ttl time.Duration) (string, error) {
+	generation := newGeneration()
+
+	err := s.Set(ctx, fmt.Sprintf(NamespaceGenerationKeyPattern, namespace), []byte(generation), store.WithExpiration(0))
+	if err != nil {
+		return "", err
+	}
+
+	g.set(namespace, generation, ttl)
+
+	return generation, nil
+}
+
+// newGeneration returns a new generation of a namespace. It is built from the
+// current time rather than incremented, so that the values of a previous
+// generation (when the generation has been evicted for instance) are never
+// reachable again.
+func newGeneration() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func (g *namespaceGenerations) set(namespace string, value string, ttl time.Duration) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if g.generations == nil {
+		g.generations = map[string]namespaceGeneration{}
+	}
+	g.generations[namespace] = namespaceGeneration{
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
+
+// generationValue returns a generation read from a store, which can return
+// it as a string or as a []byte
+func generationValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+
+	return ""
+}
@@ -0,0 +1,208 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/prodadidb/gocache/cache"
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCacheGetWhenNamespaceInvalidated(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	c := cache.New[string](store.NewMemory())
+
+	err := c.Set(ctx, cache.InNamespace("tenant-42", "my-key"), "value-42")
+	assert.Nil(t, err)
+
+	err = c.Set(ctx, cache.InNamespace("tenant-43", "my-key"), "value-43")
+	assert.Nil(t, err)
+
+	// When
+	err = c.InvalidateNamespace(ctx, "tenant-42")
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	value, err := c.Get(ctx, cache.InNamespace("tenant-43", "my-key"))
+	assert.Nil(t, err)
+	assert.Equal(t, "value-43", value)
+
+	err = c.Set(ctx, cache.InNamespace("tenant-42", "my-key"), "new-value-42")
+	assert.Nil(t, err)
+
+	value, err = c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	assert.Nil(t, err)
+	assert.Equal(t, "new-value-42", value)
+}
+
+func TestCacheGetWhenDefaultNamespace(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	c := cache.New[string](store.NewMemory(), cache.WithNamespace("products-v3"))
+
+	err := c.SetMany(ctx, map[any]string{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	})
+	assert.Nil(t, err)
+
+	values, err := c.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Len(t, values, 2)
+
+	// When
+	err = c.InvalidateNamespace(ctx, "products-v3")
+
+	// Then
+	assert.Nil(t, err)
+
+	values, err = c.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+func TestCacheGetCacheKeyWhenNamespace(t *testing.T) {
+	// Given
+	c := cache.New[string](store.NewMemory(), cache.WithNamespace("products-v3"))
+
+	// When - Then
+	assert.Equal(t, "products-v3:my-key", c.GetCacheKey("my-key"))
+	assert.Equal(t, "tenant-42:my-key", c.GetCacheKey(cache.InNamespace("tenant-42", "my-key")))
+}
+
+func TestCacheGetWhenNamespaceGenerationKeptLocally(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	generationKey := "gocache_ns_generation_tenant-42"
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Get(ctx, generationKey).Return("abc", nil)
+	s.EXPECT().Get(ctx, "tenant-42:abc:my-key").Return("my-value", nil).Times(2)
+
+	c := cache.New[string](s, cache.WithNamespaceGenerationTTL(time.Hour))
+
+	// When
+	value1, err1 := c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	value2, err2 := c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Equal(t, "my-value", value1)
+	assert.Nil(t, err2)
+	assert.Equal(t, "my-value", value2)
+}
+
+func TestCacheGetWhenNamespaceInvalidatedByAnotherInstance(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	sharedStore := store.NewMemory()
+
+	instance1 := cache.New[string](sharedStore, cache.WithNamespaceGenerationTTL(time.Hour))
+	instance2 := cache.New[string](sharedStore, cache.WithNamespaceGenerationTTL(10*time.Millisecond))
+
+	err := instance1.Set(ctx, cache.InNamespace("tenant-42", "my-key"), "my-value")
+	assert.Nil(t, err)
+
+	value, err := instance2.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+
+	// When
+	err = instance1.InvalidateNamespace(ctx, "tenant-42")
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, err := instance2.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+		return errors.Is(err, store.NotFound{})
+	}, time.Second, time.Millisecond)
+}
+
+func TestCacheGetWhenNamespaceGenerationError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to read generation")
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Get(ctx, "gocache_ns_generation_tenant-42").Return(nil, expectedErr)
+
+	c := cache.New[string](s)
+
+	// When
+	value, err := c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+
+	// Then
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, "", value)
+}
+
+func TestCacheGetWhenNamespaceInitializedConcurrently(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	generationKey := "gocache_ns_generation_tenant-42"
+
+	s := NewMockStoreInterface(ctrl)
+	gomock.InOrder(
+		s.EXPECT().Get(ctx, generationKey).Return(nil, store.NotFound{}),
+		s.EXPECT().Set(ctx, generationKey, gomock.Any(), gomock.Any()).Return(nil),
+		s.EXPECT().Get(ctx, generationKey).Return([]byte("written-by-another-instance"), nil),
+		s.EXPECT().Get(ctx, "tenant-42:written-by-another-instance:my-key").Return("my-value", nil),
+	)
+
+	c := cache.New[string](s)
+
+	// When
+	value, err := c.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-value", value)
+}
+
+func TestChainInvalidateNamespace(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	l1 := cache.New[string](store.NewMemory())
+	l2 := cache.New[string](store.NewGoCache(gocache.New(5*time.Second, 5*time.Second)))
+
+	c := cache.NewChain[string](l1, l2)
+
+	err := c.Set(ctx, cache.InNamespace("tenant-42", "my-key"), "my-value")
+	assert.Nil(t, err)
+
+	// When
+	err = c.InvalidateNamespace(ctx, "tenant-42")
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = l1.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	_, err = l2.Get(ctx, cache.InNamespace("tenant-42", "my-key"))
+	assert.True(t, errors.Is(err, store.NotFound{}))
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultQueueSize is the default number of values waiting to be set in
+	// background by a chain or loadable cache
+	DefaultQueueSize = 10000
+	// DefaultQueueWorkers is the default number of goroutines setting values
+	// in background for a chain or loadable cache
+	DefaultQueueWorkers = 1
+)
+
+// QueueOptions defines how a chain or loadable cache sets values in background
+// (values found in a next cache of a chain, written behind in its next caches,
+// or returned by a load function).
+type QueueOptions struct {
+	// Size is the number of values waiting to be set, DefaultQueueSize when
+	// zero
+	Size int
+	// Workers is the number of goroutines setting the values,
+	// DefaultQueueWorkers when zero. With several workers, the values of a
+	// same key may be set in a different order than they were queued in.
+	Workers int
+	// DropOnFull drops the values queued while the queue is full instead of
+	// waiting for a free slot, so that reads are never slowed down by a slow
+	// cache. Dropped values are counted.
+	DropOnFull bool
+	// Sync sets the values before returning, without queue nor goroutine
+	// (mainly useful in tests)
+	Sync bool
+}
+
+func (o QueueOptions) size() int {
+	if o.Size <= 0 {
+		return DefaultQueueSize
+	}
+
+	return o.Size
+}
+
+func (o QueueOptions) workers() int {
+	if o.Workers <= 0 {
+		return DefaultQueueWorkers
+	}
+
+	return o.Workers
+}
+
+// queue hands values to a function called by a pool of goroutines, until
+// closed
+type queue[I any] struct {
+	items   chan I
+	wg      *sync.WaitGroup
+	handle  func(item I)
+	options QueueOptions
+
+	// mtx prevents values from being pushed while the channel is closed
+	mtx     sync.RWMutex
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// newQueue returns a queue calling the given function for each value pushed,
+// and starts its goroutines
+func newQueue[I any](options QueueOptions, handle func(item I)) *queue[I] {
+	q := &queue[I]{
+		wg:      &sync.WaitGroup{},
+		handle:  handle,
+		options: options,
+	}
+
+	if options.Sync {
+		return q
+	}
+
+	q.items = make(chan I, options.size())
+	for i := 0; i < options.workers(); i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+func (q *queue[I]) work() {
+	defer q.wg.Done()
+
+	for item := range q.items {
+		q.handle(item)
+	}
+}
+
+// push queues the given value, or handles it right away in synchronous mode,
+// and returns false when the value has been dropped. Values pushed once the
+// queue is closed are dropped.
+func (q *queue[I]) push(item I) bool {
+	if q.options.Sync {
+		q.handle(item)
+		return true
+	}
+
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+
+	if q.closed {
+		q.dropped.Add(1)
+		return false
+	}
+
+	if !q.options.DropOnFull {
+		q.items <- item
+		return true
+	}
+
+	select {
+	case q.items <- item:
+		return true
+	default:
+		q.dropped.Add(1)
+		return false
+	}
+}
+
+// close stops the queue once the values already queued have been handled
+func (q *queue[I]) close() {
+	q.mtx.Lock()
+	if !q.closed && q.items != nil {
+		close(q.items)
+	}
+	q.closed = true
+	q.mtx.Unlock()
+
+	q.wg.Wait()
+}
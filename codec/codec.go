@@ -67,6 +67,18 @@ func (c *Codec) GetWithTTL(ctx context.Context, key any) (any, time.Duration, er
 	return val, ttl, err
 }
 
+// GetMany allows to retrieve the values from given key identifiers
+func (c *Codec) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values, err := c.store.GetMany(ctx, keys)
+
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	c.stats.Hits += len(values)
+	c.stats.Miss += len(keys) - len(values)
+
+	return values, err
+}
+
 // Set allows to set a value for a given key identifier and also allows to specify
 // an expiration time
 func (c *Codec) Set(ctx context.Context, key any, value any, options ...store.Option) error {
@@ -83,6 +95,22 @@ func (c *Codec) Set(ctx context.Context, key any, value any, options ...store.Op
 	return err
 }
 
+// SetMany allows to set values for given key identifiers and also allows to
+// specify an expiration time
+func (c *Codec) SetMany(ctx context.Context, items map[any]any, options ...store.Option) error {
+	err := c.store.SetMany(ctx, items, options...)
+
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	if err == nil {
+		c.stats.SetSuccess += len(items)
+	} else {
+		c.stats.SetError += len(items)
+	}
+
+	return err
+}
+
 // Delete allows to remove a value for a given key identifier
 func (c *Codec) Delete(ctx context.Context, key any) error {
 	err := c.store.Delete(ctx, key)
@@ -98,6 +126,21 @@ func (c *Codec) Delete(ctx context.Context, key any) error {
 	return err
 }
 
+// DeleteMany allows to remove values for given key identifiers
+func (c *Codec) DeleteMany(ctx context.Context, keys []any) error {
+	err := c.store.DeleteMany(ctx, keys)
+
+	c.statsMtx.Lock()
+	defer c.statsMtx.Unlock()
+	if err == nil {
+		c.stats.DeleteSuccess += len(keys)
+	} else {
+		c.stats.DeleteError += len(keys)
+	}
+
+	return err
+}
+
 // Invalidate invalidates some cach items from given options
 func (c *Codec) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
 	err := c.store.Invalidate(ctx, options...)
@@ -449,3 +449,76 @@ func TestGetStats(t *testing.T) {
 	expectedStats := &codec.Stats{}
 	assert.Equal(t, expectedStats, c.GetStats())
 }
+
+func TestGetManyWhenPartialHit(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().GetMany(ctx, []any{"key-1", "key-2", "key-3"}).Return(map[any]any{
+		"key-1": "value-1",
+		"key-3": "value-3",
+	}, nil)
+
+	c := codec.New(s)
+
+	// When
+	values, err := c.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1", "key-3": "value-3"}, values)
+
+	assert.Equal(t, 2, c.GetStats().Hits)
+	assert.Equal(t, 1, c.GetStats().Miss)
+}
+
+func TestSetManyWhenSuccess(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	items := map[any]any{"key-1": "value-1", "key-2": "value-2"}
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().SetMany(ctx, items, store.OptionsMatcher{
+		Expiration: 5 * time.Second,
+	}).Return(nil)
+
+	c := codec.New(s)
+
+	// When
+	err := c.SetMany(ctx, items, store.WithExpiration(5*time.Second))
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, c.GetStats().SetSuccess)
+	assert.Equal(t, 0, c.GetStats().SetError)
+}
+
+func TestDeleteManyWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to delete keys")
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().DeleteMany(ctx, []any{"key-1", "key-2"}).Return(expectedErr)
+
+	c := codec.New(s)
+
+	// When
+	err := c.DeleteMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Equal(t, expectedErr, err)
+
+	assert.Equal(t, 0, c.GetStats().DeleteSuccess)
+	assert.Equal(t, 2, c.GetStats().DeleteError)
+}
@@ -13,8 +13,11 @@ import (
 type CodecInterface interface {
 	Get(ctx context.Context, key any) (any, error)
 	GetWithTTL(ctx context.Context, key any) (any, time.Duration, error)
+	GetMany(ctx context.Context, keys []any) (map[any]any, error)
 	Set(ctx context.Context, key any, value any, options ...store.Option) error
+	SetMany(ctx context.Context, items map[any]any, options ...store.Option) error
 	Delete(ctx context.Context, key any) error
+	DeleteMany(ctx context.Context, keys []any) error
 	Invalidate(ctx context.Context, options ...store.InvalidateOption) error
 	Clear(ctx context.Context) error
 
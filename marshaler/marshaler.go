@@ -5,18 +5,24 @@ import (
 
 	"github.com/prodadidb/gocache/cache"
 	"github.com/prodadidb/gocache/store"
-	"github.com/vmihailenco/msgpack"
 )
 
 // Marshaler is the struct that marshal and unmarshal cache values
 type Marshaler struct {
-	Cache cache.CacheInterface[any]
+	Cache   cache.CacheInterface[any]
+	Options *Options
+
+	serializers map[Format]Serializer
 }
 
 // New creates a new marshaler that marshals/unmarshals cache values
-func New(cache cache.CacheInterface[any]) *Marshaler {
+func New(cache cache.CacheInterface[any], options ...Option) *Marshaler {
+	opts := applyOptions(options...)
+
 	return &Marshaler{
-		Cache: cache,
+		Cache:       cache,
+		Options:     opts,
+		serializers: readSerializers(opts),
 	}
 }
 
@@ -29,9 +35,9 @@ func (c *Marshaler) Get(ctx context.Context, key any, returnObj any) (any, error
 
 	switch v := result.(type) {
 	case []byte:
-		err = msgpack.Unmarshal(v, returnObj)
+		err = decode(c.serializers, v, returnObj)
 	case string:
-		err = msgpack.Unmarshal([]byte(v), returnObj)
+		err = decode(c.serializers, []byte(v), returnObj)
 	}
 
 	if err != nil {
@@ -43,7 +49,7 @@ func (c *Marshaler) Get(ctx context.Context, key any, returnObj any) (any, error
 
 // Set sets a value in cache by marshaling value
 func (c *Marshaler) Set(ctx context.Context, key, object any, options ...store.Option) error {
-	bytes, err := msgpack.Marshal(object)
+	bytes, err := marshal(c.Options, object)
 	if err != nil {
 		return err
 	}
@@ -327,3 +327,102 @@ func TestClearWhenError(t *testing.T) {
 	// Then
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestSetWhenSerializer(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := &testCacheValue{
+		Hello: "world",
+	}
+
+	cache := NewMockCacheInterface[any](ctrl)
+	cache.EXPECT().Set(
+		ctx,
+		"my-key",
+		append([]byte{0xc1, byte(marshaler.FormatJSON)}, []byte(`{"Hello":"world"}`)...),
+		store.OptionsMatcher{
+			Expiration: 5 * time.Second,
+		},
+	).Return(nil)
+
+	m := marshaler.New(cache, marshaler.WithSerializer(marshaler.JSONSerializer{}))
+
+	// When
+	err := m.Set(ctx, "my-key", cacheValue, store.WithExpiration(5*time.Second))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestGetWhenWrittenWithAnotherSerializer(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := &testCacheValue{
+		Hello: "world",
+	}
+
+	cacheValueBytes := append([]byte{0xc1, byte(marshaler.FormatJSON)}, []byte(`{"Hello":"world"}`)...)
+
+	cache := NewMockCacheInterface[any](ctrl)
+	cache.EXPECT().Get(ctx, "my-key").Return(cacheValueBytes, nil)
+
+	m := marshaler.New(cache, marshaler.WithSerializer(marshaler.GobSerializer{}))
+
+	// When
+	value, err := m.Get(ctx, "my-key", new(testCacheValue))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestGetWhenLegacyMsgpackAndSerializer(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := &testCacheValue{
+		Hello: "world",
+	}
+
+	cacheValueBytes, err := msgpack.Marshal(cacheValue)
+	assert.Nil(t, err)
+
+	cache := NewMockCacheInterface[any](ctrl)
+	cache.EXPECT().Get(ctx, "my-key").Return(cacheValueBytes, nil)
+
+	m := marshaler.New(cache, marshaler.WithSerializer(marshaler.JSONSerializer{}))
+
+	// When
+	value, err := m.Get(ctx, "my-key", new(testCacheValue))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestGetWhenUnknownFormat(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cache := NewMockCacheInterface[any](ctrl)
+	cache.EXPECT().Get(ctx, "my-key").Return([]byte{0xc1, 0x7f, 0x00}, nil)
+
+	m := marshaler.New(cache)
+
+	// When
+	value, err := m.Get(ctx, "my-key", new(testCacheValue))
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, marshaler.ErrUnknownFormat)
+}
@@ -0,0 +1,29 @@
+package marshaler
+
+// Option represents a marshaler option function.
+type Option func(o *Options)
+
+type Options struct {
+	Serializer Serializer
+}
+
+func applyOptions(opts ...Option) *Options {
+	o := &Options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSerializer allows to specify the serializer used to write values. Values
+// are written along with a header identifying their format, so that they can
+// be read back whatever the serializer used by the reader. When no serializer
+// is given, values are written using msgpack without header, as done by
+// previous versions.
+func WithSerializer(serializer Serializer) Option {
+	return func(o *Options) {
+		o.Serializer = serializer
+	}
+}
@@ -0,0 +1,185 @@
+package marshaler
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format identifies the serialization format of a stored value
+type Format byte
+
+const (
+	// FormatMsgpack identifies values serialized using msgpack
+	FormatMsgpack Format = 0x01
+	// FormatJSON identifies values serialized using encoding/json
+	FormatJSON Format = 0x02
+	// FormatGob identifies values serialized using encoding/gob
+	FormatGob Format = 0x03
+	// FormatProtobuf identifies values serialized using protocol buffers
+	FormatProtobuf Format = 0x04
+)
+
+// headerMagic starts the header written in front of serialized values. It is
+// never used by msgpack, so that values written without a header (by previous
+// versions) can still be read as msgpack.
+const headerMagic byte = 0xc1
+
+// headerSize is the size of the header: the magic byte followed by the format
+const headerSize = 2
+
+var (
+	// ErrUnknownFormat is returned when reading a value written with a format
+	// that has no registered serializer
+	ErrUnknownFormat = errors.New("unknown serialization format")
+	// ErrNotProtoMessage is returned by the protobuf serializer when the given
+	// value does not implement proto.Message
+	ErrNotProtoMessage = errors.New("value does not implement proto.Message")
+)
+
+// Serializer represents a serialization format of cache values
+type Serializer interface {
+	Marshal(value any) ([]byte, error)
+	Unmarshal(data []byte, returnObj any) error
+	Format() Format
+}
+
+// MsgpackSerializer serializes values using vmihailenco/msgpack
+type MsgpackSerializer struct{}
+
+func (MsgpackSerializer) Marshal(value any) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (MsgpackSerializer) Unmarshal(data []byte, returnObj any) error {
+	return msgpack.Unmarshal(data, returnObj)
+}
+
+func (MsgpackSerializer) Format() Format {
+	return FormatMsgpack
+}
+
+// JSONSerializer serializes values using encoding/json
+type JSONSerializer struct{}
+
+func (JSONSerializer) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONSerializer) Unmarshal(data []byte, returnObj any) error {
+	return json.Unmarshal(data, returnObj)
+}
+
+func (JSONSerializer) Format() Format {
+	return FormatJSON
+}
+
+// GobSerializer serializes values using encoding/gob
+type GobSerializer struct{}
+
+func (GobSerializer) Marshal(value any) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	if err := gob.NewEncoder(buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (GobSerializer) Unmarshal(data []byte, returnObj any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(returnObj)
+}
+
+func (GobSerializer) Format() Format {
+	return FormatGob
+}
+
+// ProtobufSerializer serializes values implementing proto.Message
+type ProtobufSerializer struct{}
+
+func (ProtobufSerializer) Marshal(value any) ([]byte, error) {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return nil, ErrNotProtoMessage
+	}
+	return proto.Marshal(message)
+}
+
+func (ProtobufSerializer) Unmarshal(data []byte, returnObj any) error {
+	message, ok := returnObj.(proto.Message)
+	if !ok {
+		return ErrNotProtoMessage
+	}
+	return proto.Unmarshal(data, message)
+}
+
+func (ProtobufSerializer) Format() Format {
+	return FormatProtobuf
+}
+
+// builtinSerializers are the serializers always available to read values
+var builtinSerializers = []Serializer{
+	MsgpackSerializer{},
+	JSONSerializer{},
+	GobSerializer{},
+	ProtobufSerializer{},
+}
+
+// readSerializers returns the serializers able to read values, indexed by
+// format: the built-in ones along with the configured one
+func readSerializers(options *Options) map[Format]Serializer {
+	serializers := make(map[Format]Serializer, len(builtinSerializers)+1)
+
+	for _, serializer := range builtinSerializers {
+		serializers[serializer.Format()] = serializer
+	}
+	if serializer := options.Serializer; serializer != nil {
+		serializers[serializer.Format()] = serializer
+	}
+
+	return serializers
+}
+
+// marshal serializes the given value using the configured serializer, or
+// using msgpack without header when no serializer is configured
+func marshal(options *Options, value any) ([]byte, error) {
+	if options.Serializer == nil {
+		return msgpack.Marshal(value)
+	}
+
+	return encode(options.Serializer, value)
+}
+
+// encode serializes the given value and prefixes it with the format header
+func encode(serializer Serializer, value any) ([]byte, error) {
+	data, err := serializer.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, headerSize+len(data))
+	result = append(result, headerMagic, byte(serializer.Format()))
+
+	return append(result, data...), nil
+}
+
+// decode reads the given value using the serializer of the format written in
+// its header. Values without header are read as msgpack.
+func decode(serializers map[Format]Serializer, data []byte, returnObj any) error {
+	if len(data) < headerSize || data[0] != headerMagic {
+		return MsgpackSerializer{}.Unmarshal(data, returnObj)
+	}
+
+	format := Format(data[1])
+
+	serializer, ok := serializers[format]
+	if !ok {
+		return fmt.Errorf("%w: 0x%02x", ErrUnknownFormat, byte(format))
+	}
+
+	return serializer.Unmarshal(data[headerSize:], returnObj)
+}
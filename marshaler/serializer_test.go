@@ -0,0 +1,72 @@
+package marshaler_test
+
+import (
+	"testing"
+
+	"github.com/prodadidb/gocache/marshaler"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestSerializers(t *testing.T) {
+	testCases := []struct {
+		serializer marshaler.Serializer
+		format     marshaler.Format
+	}{
+		{marshaler.MsgpackSerializer{}, marshaler.FormatMsgpack},
+		{marshaler.JSONSerializer{}, marshaler.FormatJSON},
+		{marshaler.GobSerializer{}, marshaler.FormatGob},
+	}
+
+	for _, testCase := range testCases {
+		// Given
+		cacheValue := &testCacheValue{
+			Hello: "world",
+		}
+
+		// When
+		data, err := testCase.serializer.Marshal(cacheValue)
+		assert.Nil(t, err)
+
+		value := new(testCacheValue)
+		err = testCase.serializer.Unmarshal(data, value)
+
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, cacheValue, value)
+		assert.Equal(t, testCase.format, testCase.serializer.Format())
+	}
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	// Given
+	serializer := marshaler.ProtobufSerializer{}
+
+	cacheValue := wrapperspb.String("world")
+
+	// When
+	data, err := serializer.Marshal(cacheValue)
+	assert.Nil(t, err)
+
+	value := new(wrapperspb.StringValue)
+	err = serializer.Unmarshal(data, value)
+
+	// Then
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(cacheValue, value))
+	assert.Equal(t, marshaler.FormatProtobuf, serializer.Format())
+}
+
+func TestProtobufSerializerWhenNotProtoMessage(t *testing.T) {
+	// Given
+	serializer := marshaler.ProtobufSerializer{}
+
+	// When
+	_, marshalErr := serializer.Marshal(&testCacheValue{Hello: "world"})
+	unmarshalErr := serializer.Unmarshal([]byte{}, new(testCacheValue))
+
+	// Then
+	assert.Equal(t, marshaler.ErrNotProtoMessage, marshalErr)
+	assert.Equal(t, marshaler.ErrNotProtoMessage, unmarshalErr)
+}
@@ -0,0 +1,180 @@
+package marshaler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/prodadidb/gocache/cache"
+	"github.com/prodadidb/gocache/codec"
+	"github.com/prodadidb/gocache/store"
+)
+
+const (
+	// TypedMarshalerType represents the typed marshaler cache type as a string value
+	TypedMarshalerType = "marshaler"
+)
+
+// Payload represents the value types of the caches a TypedMarshaler can wrap
+type Payload interface {
+	[]byte | string
+}
+
+// TypedMarshaler is a cache of T values that marshals and unmarshals them
+// into a cache of []byte or string values. It implements both
+// cache.CacheInterface[T] and cache.SetterCacheInterface[T], so that it can be
+// wrapped by other caches (loadable, metric, chain, ...).
+type TypedMarshaler[T any, V Payload] struct {
+	Cache   cache.CacheInterface[V]
+	Options *Options
+
+	serializers map[Format]Serializer
+}
+
+// NewTyped creates a new typed marshaler that marshals/unmarshals values of
+// type T into the given cache of []byte values
+func NewTyped[T any](cache cache.CacheInterface[[]byte], options ...Option) *TypedMarshaler[T, []byte] {
+	return newTyped[T](cache, options...)
+}
+
+// NewTypedString creates a new typed marshaler that marshals/unmarshals values
+// of type T into the given cache of string values (Redis for instance)
+func NewTypedString[T any](cache cache.CacheInterface[string], options ...Option) *TypedMarshaler[T, string] {
+	return newTyped[T](cache, options...)
+}
+
+func newTyped[T any, V Payload](cache cache.CacheInterface[V], options ...Option) *TypedMarshaler[T, V] {
+	opts := applyOptions(options...)
+
+	return &TypedMarshaler[T, V]{
+		Cache:       cache,
+		Options:     opts,
+		serializers: readSerializers(opts),
+	}
+}
+
+// Get obtains a value from cache and unmarshals it
+func (c *TypedMarshaler[T, V]) Get(ctx context.Context, key any) (T, error) {
+	value, err := c.Cache.Get(ctx, key)
+	if err != nil {
+		return *new(T), err
+	}
+
+	return c.unmarshal(value)
+}
+
+// GetWithTTL obtains a value from cache, unmarshals it and returns its TTL.
+// The TTL is only known when the wrapped cache is a setter cache.
+func (c *TypedMarshaler[T, V]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
+	setterCache, ok := c.Cache.(cache.SetterCacheInterface[V])
+	if !ok {
+		object, err := c.Get(ctx, key)
+		return object, 0, err
+	}
+
+	value, ttl, err := setterCache.GetWithTTL(ctx, key)
+	if err != nil {
+		return *new(T), ttl, err
+	}
+
+	object, err := c.unmarshal(value)
+	return object, ttl, err
+}
+
+// GetMany obtains values from cache and unmarshals them
+func (c *TypedMarshaler[T, V]) GetMany(ctx context.Context, keys []any) (map[any]T, error) {
+	values, err := c.Cache.GetMany(ctx, keys)
+
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	objects := make(map[any]T, len(values))
+	for key, value := range values {
+		object, err := c.unmarshal(value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		objects[key] = object
+	}
+
+	return objects, errors.Join(errs...)
+}
+
+// Set sets a value in cache by marshaling it
+func (c *TypedMarshaler[T, V]) Set(ctx context.Context, key any, object T, options ...store.Option) error {
+	data, err := marshal(c.Options, object)
+	if err != nil {
+		return err
+	}
+
+	return c.Cache.Set(ctx, key, V(data), options...)
+}
+
+// SetMany sets values in cache by marshaling them
+func (c *TypedMarshaler[T, V]) SetMany(ctx context.Context, items map[any]T, options ...store.Option) error {
+	values := make(map[any]V, len(items))
+	for key, object := range items {
+		data, err := marshal(c.Options, object)
+		if err != nil {
+			return err
+		}
+		values[key] = V(data)
+	}
+
+	return c.Cache.SetMany(ctx, values, options...)
+}
+
+// Delete removes a value from the cache
+func (c *TypedMarshaler[T, V]) Delete(ctx context.Context, key any) error {
+	return c.Cache.Delete(ctx, key)
+}
+
+// DeleteMany removes values from the cache
+func (c *TypedMarshaler[T, V]) DeleteMany(ctx context.Context, keys []any) error {
+	return c.Cache.DeleteMany(ctx, keys)
+}
+
+// Invalidate invalidate cache values using given options
+func (c *TypedMarshaler[T, V]) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	return c.Cache.Invalidate(ctx, options...)
+}
+
+// Clear reset all cache data
+func (c *TypedMarshaler[T, V]) Clear(ctx context.Context) error {
+	return c.Cache.Clear(ctx)
+}
+
+// GetCodec returns the codec of the wrapped cache, looked up through the
+// metric and loadable caches wrapping it, nil when it has none (a chain cache
+// for instance)
+func (c *TypedMarshaler[T, V]) GetCodec() codec.CodecInterface {
+	return cache.CodecOf[V](c.Cache)
+}
+
+// GetType returns the cache type
+func (c *TypedMarshaler[T, V]) GetType() string {
+	return TypedMarshalerType
+}
+
+// unmarshal returns the object serialized in the given value. Pointer types
+// are allocated so that values implementing proto.Message can be read.
+func (c *TypedMarshaler[T, V]) unmarshal(value V) (T, error) {
+	var object T
+
+	returnObj := any(&object)
+	if typ := reflect.TypeOf(object); typ != nil && typ.Kind() == reflect.Pointer {
+		pointer := reflect.New(typ.Elem())
+		object = pointer.Interface().(T)
+		returnObj = object
+	}
+
+	if err := decode(c.serializers, []byte(value), returnObj); err != nil {
+		return *new(T), err
+	}
+
+	return object, nil
+}
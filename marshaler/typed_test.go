@@ -0,0 +1,212 @@
+package marshaler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/prodadidb/gocache/cache"
+	"github.com/prodadidb/gocache/marshaler"
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewTyped(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cache1 := NewMockCacheInterface[[]byte](ctrl)
+
+	// When
+	m := marshaler.NewTyped[testCacheValue](cache1)
+
+	// Then
+	assert.IsType(t, new(marshaler.TypedMarshaler[testCacheValue, []byte]), m)
+	assert.Equal(t, cache1, m.Cache)
+	assert.Equal(t, marshaler.TypedMarshalerType, m.GetType())
+}
+
+func TestTypedGetWhenByteCache(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	m := marshaler.NewTyped[testCacheValue](cache.New[[]byte](store.NewMemory()))
+
+	cacheValue := testCacheValue{
+		Hello: "world",
+	}
+
+	err := m.Set(ctx, "my-key", cacheValue)
+	assert.Nil(t, err)
+
+	// When
+	value, err := m.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestTypedGetWithTTLWhenStringCache(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	m := marshaler.NewTypedString[*testCacheValue](
+		cache.New[string](store.NewMemory()),
+		marshaler.WithSerializer(marshaler.JSONSerializer{}),
+	)
+
+	cacheValue := &testCacheValue{
+		Hello: "world",
+	}
+
+	err := m.Set(ctx, "my-key", cacheValue, store.WithExpiration(10*time.Second))
+	assert.Nil(t, err)
+
+	// When
+	value, ttl, err := m.GetWithTTL(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+	assert.True(t, ttl > 9*time.Second && ttl <= 10*time.Second)
+}
+
+func TestTypedGetWhenProtobuf(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	m := marshaler.NewTyped[*wrapperspb.StringValue](
+		cache.New[[]byte](store.NewMemory()),
+		marshaler.WithSerializer(marshaler.ProtobufSerializer{}),
+	)
+
+	cacheValue := wrapperspb.String("world")
+
+	err := m.Set(ctx, "my-key", cacheValue)
+	assert.Nil(t, err)
+
+	// When
+	value, err := m.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(cacheValue, value))
+}
+
+func TestTypedGetWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to find item in store")
+
+	cache1 := NewMockCacheInterface[[]byte](ctrl)
+	cache1.EXPECT().Get(ctx, "my-key").Return(nil, expectedErr)
+
+	m := marshaler.NewTyped[testCacheValue](cache1)
+
+	// When
+	value, err := m.Get(ctx, "my-key")
+
+	// Then
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, testCacheValue{}, value)
+}
+
+func TestTypedGetMany(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	m := marshaler.NewTyped[testCacheValue](cache.New[[]byte](store.NewMemory()))
+
+	err := m.SetMany(ctx, map[any]testCacheValue{
+		"key-1": {Hello: "world"},
+		"key-2": {Hello: "folks"},
+	})
+	assert.Nil(t, err)
+
+	// When
+	values, err := m.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]testCacheValue{
+		"key-1": {Hello: "world"},
+		"key-2": {Hello: "folks"},
+	}, values)
+}
+
+func TestTypedWhenChainAndLoadable(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	sharedStore := store.NewGoCache(gocache.New(5*time.Second, 5*time.Second))
+
+	l1 := marshaler.NewTyped[testCacheValue](cache.New[[]byte](memoryStore))
+	l2 := marshaler.NewTypedString[testCacheValue](cache.New[string](sharedStore))
+
+	loadFunc := func(_ context.Context, key any) (testCacheValue, error) {
+		return testCacheValue{Hello: "loaded"}, nil
+	}
+
+	ch := cache.NewLoadable[testCacheValue](loadFunc, cache.NewChain[testCacheValue](l1, l2))
+
+	err := l2.Set(ctx, "in-l2", testCacheValue{Hello: "world"})
+	assert.Nil(t, err)
+
+	// When
+	value, err := ch.Get(ctx, "in-l2")
+	assert.Nil(t, err)
+
+	loadedValue, loadErr := ch.Get(ctx, "missing")
+
+	// Then
+	assert.Equal(t, testCacheValue{Hello: "world"}, value)
+
+	assert.Nil(t, loadErr)
+	assert.Equal(t, testCacheValue{Hello: "loaded"}, loadedValue)
+
+	assert.Eventually(t, func() bool {
+		value, err := l1.Get(ctx, "in-l2")
+		return err == nil && value.Hello == "world"
+	}, time.Second, time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		value, err := sharedStore.Get(ctx, "missing")
+		_, isString := value.(string)
+		return err == nil && isString
+	}, time.Second, time.Millisecond)
+}
+
+func TestTypedGetCodecWhenMetricCache(t *testing.T) {
+	// Given
+	memoryStore := store.NewMemory()
+
+	cache1 := cache.New[[]byte](memoryStore)
+	metricCache := cache.NewMetric[[]byte](nil, cache1)
+
+	m := marshaler.NewTyped[testCacheValue](metricCache)
+
+	// When
+	codec := m.GetCodec()
+
+	// Then
+	assert.Equal(t, cache1.GetCodec(), codec)
+	assert.Equal(t, memoryStore, codec.GetStore())
+}
+
+func TestTypedGetCodecWhenChainCache(t *testing.T) {
+	// Given
+	m := marshaler.NewTyped[testCacheValue](cache.NewChain[[]byte](cache.New[[]byte](store.NewMemory())))
+
+	// When - Then
+	assert.Nil(t, m.GetCodec())
+}
@@ -8,3 +8,10 @@ import "github.com/prodadidb/gocache/codec"
 type MetricsInterface interface {
 	RecordFromCodec(codec codec.CodecInterface)
 }
+
+// Recorder is implemented by metrics providers recording any metric of a
+// store type, such as the statistics of chain and loadable caches. The metric
+// cache records them only when its provider implements it.
+type Recorder interface {
+	Record(store, metric string, value float64)
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"github.com/prodadidb/gocache/codec"
+	"github.com/prodadidb/gocache/store"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -53,7 +54,8 @@ func (m *Prometheus) Record(store, metric string, value float64) {
 func (m *Prometheus) recorder() {
 	for codec := range m.CodecChannel {
 		stats := codec.GetStats()
-		storeType := codec.GetStore().GetType()
+		codecStore := codec.GetStore()
+		storeType := codecStore.GetType()
 
 		m.Record(storeType, "hit_count", float64(stats.Hits))
 		m.Record(storeType, "miss_count", float64(stats.Miss))
@@ -66,6 +68,14 @@ func (m *Prometheus) recorder() {
 
 		m.Record(storeType, "invalidate_success", float64(stats.InvalidateSuccess))
 		m.Record(storeType, "invalidate_error", float64(stats.InvalidateError))
+
+		if provider, ok := codecStore.(store.CompressionStatsProvider); ok {
+			compressionStats := provider.GetCompressionStats()
+
+			m.Record(storeType, "compressed_count", float64(compressionStats.Compressed))
+			m.Record(storeType, "uncompressed_count", float64(compressionStats.Uncompressed))
+			m.Record(storeType, "compression_ratio", compressionStats.Ratio())
+		}
 	}
 }
 
@@ -1,11 +1,14 @@
 package metrics_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/prodadidb/gocache/codec"
 	"github.com/prodadidb/gocache/metrics"
+	"github.com/prodadidb/gocache/store"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -127,3 +130,29 @@ func TestRecordFromCodec(t *testing.T) {
 		assert.Equal(t, tc.expected, v)
 	}
 }
+
+func TestRecordFromCodecWhenCompressionStore(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	compressionStore := store.NewCompression(store.NewMemory(), store.WithCompressionThreshold(10))
+	testCodec := codec.New(compressionStore)
+
+	err := testCodec.Set(ctx, "my-key", []byte(strings.Repeat("my-value", 100)))
+	assert.Nil(t, err)
+
+	m := metrics.NewPrometheus("my-test-service-name")
+
+	// When
+	m.RecordFromCodec(testCodec)
+
+	// Then
+	assert.Eventually(t, func() bool {
+		metric, err := m.Collector.GetMetricWithLabelValues("my-test-service-name", store.MemoryType, "compression_ratio")
+		return err == nil && testutil.ToFloat64(metric) == compressionStore.GetCompressionStats().Ratio()
+	}, time.Second, time.Millisecond)
+
+	metric, err := m.Collector.GetMetricWithLabelValues("my-test-service-name", store.MemoryType, "compressed_count")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metric))
+}
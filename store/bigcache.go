@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
+
+	"github.com/allegro/bigcache/v3"
 )
 
 //go:generate mockgen -destination=./mock_store_bigcache_interface_test.go -package=store_test -source=bigcache.go
@@ -16,6 +17,7 @@ type BigcacheClientInterface interface {
 	Set(key string, entry []byte) error
 	Delete(key string) error
 	Reset() error
+	Iterator() *bigcache.EntryInfoIterator
 }
 
 const (
@@ -27,16 +29,22 @@ const (
 
 // BigcacheStore is a store for Bigcache
 type BigcacheStore struct {
-	Client  BigcacheClientInterface
-	Options *Options
+	Client   BigcacheClientInterface
+	Options  *Options
+	TagIndex TagIndex
 }
 
 // NewBigcache creates a new store to Bigcache instance(s)
-func NewBigcache(client BigcacheClientInterface, options ...Option) *BigcacheStore {
-	return &BigcacheStore{
+func NewBigcache(client BigcacheClientInterface, options ...StoreOption) *BigcacheStore {
+	storeOptions := applyStoreOptions(options...)
+
+	s := &BigcacheStore{
 		Client:  client,
-		Options: applyOptions(options...),
+		Options: applyOptions(storeOptions.DefaultOptions...),
 	}
+	s.TagIndex = storeOptions.tagIndex(bigcacheTagStorage{store: s})
+
+	return s
 }
 
 // Get returns data stored from a given key
@@ -52,6 +60,27 @@ func (s *BigcacheStore) Get(_ context.Context, key any) (any, error) {
 	return item, err
 }
 
+// GetMany returns data stored from given keys
+func (s *BigcacheStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	errs := []error{}
+
+	for _, key := range keys {
+		item, err := s.Get(ctx, key)
+		if errors.Is(err, bigcache.ErrEntryNotFound) || errors.Is(err, NotFound{}) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		values[key] = item
+	}
+
+	return values, errors.Join(errs...)
+}
+
 // GetWithTTL returns data stored from a given key and its corresponding TTL
 func (s *BigcacheStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
 	item, err := s.Get(ctx, key)
@@ -78,37 +107,15 @@ func (s *BigcacheStore) Set(ctx context.Context, key any, value any, options ...
 	}
 
 	if tags := opts.Tags; len(tags) > 0 {
-		s.setTags(ctx, key, tags)
+		return s.TagIndex.Add(ctx, key.(string), opts.Expiration, tags)
 	}
 
 	return nil
 }
 
-func (s *BigcacheStore) setTags(ctx context.Context, key any, tags []string) {
-	for _, tag := range tags {
-		tagKey := fmt.Sprintf(BigcacheTagPattern, tag)
-		cacheKeys := []string{}
-
-		if result, err := s.Get(ctx, tagKey); err == nil {
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
-			}
-		}
-
-		alreadyInserted := false
-		for _, cacheKey := range cacheKeys {
-			if cacheKey == key.(string) {
-				alreadyInserted = true
-				break
-			}
-		}
-
-		if !alreadyInserted {
-			cacheKeys = append(cacheKeys, key.(string))
-		}
-
-		_ = s.Set(ctx, tagKey, []byte(strings.Join(cacheKeys, ",")), WithExpiration(720*time.Hour))
-	}
+// SetMany defines data in Bigcache for given key identifiers
+func (s *BigcacheStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, s, items, options...)
 }
 
 // Delete removes data from Bigcache for given key identifier
@@ -116,30 +123,58 @@ func (s *BigcacheStore) Delete(_ context.Context, key any) error {
 	return s.Client.Delete(key.(string))
 }
 
+// DeleteMany removes data from Bigcache for given key identifiers
+func (s *BigcacheStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, s, keys)
+}
+
 // Invalidate invalidates some cache data in Bigcache for given options
 func (s *BigcacheStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
+	errs := []error{}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(BigcacheTagPattern, tag)
-			result, err := s.Get(ctx, tagKey)
+	for _, tag := range opts.Tags {
+		cacheKeys, err := s.TagIndex.Pop(ctx, tag)
+		if err == nil {
+			err = opts.deleteTagKeys(tag, cacheKeys, s.deleteKey)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate tag %q: %w", tag, err))
+		}
+	}
+
+	if opts.matchesKeys() {
+		// Keys are deleted once iterated, as deleting them while iterating
+		// would skip some of them
+		cacheKeys := []string{}
+
+		iterator := s.Client.Iterator()
+		for iterator.SetNext() {
+			entry, err := iterator.Value()
 			if err != nil {
-				return nil
+				return errors.Join(append(errs, err)...)
 			}
-
-			cacheKeys := []string{}
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
+			if opts.matchKey(entry.Key()) {
+				cacheKeys = append(cacheKeys, entry.Key())
 			}
+		}
 
-			for _, cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
-			}
+		if err := opts.deleteKeys(cacheKeys, s.deleteKey); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// deleteKey deletes the given key, returning whether it existed
+func (s *BigcacheStore) deleteKey(key string) (bool, error) {
+	err := s.Client.Delete(key)
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
 }
 
 // Clear resets all data in the store
@@ -151,3 +186,31 @@ func (s *BigcacheStore) Clear(_ context.Context) error {
 func (s *BigcacheStore) GetType() string {
 	return BigcacheType
 }
+
+// bigcacheTagStorage stores the tag index of a BigcacheStore in Bigcache
+type bigcacheTagStorage struct {
+	store *BigcacheStore
+}
+
+func (t bigcacheTagStorage) LoadTag(_ context.Context, tag string) ([]byte, any, error) {
+	value, err := t.store.Client.Get(fmt.Sprintf(BigcacheTagPattern, tag))
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return nil, nil, nil
+	}
+
+	return value, nil, err
+}
+
+func (t bigcacheTagStorage) StoreTag(_ context.Context, tag string, members []byte, _ any, _ time.Duration) error {
+	// Bigcache entries all share the life window of the cache
+	return t.store.Client.Set(fmt.Sprintf(BigcacheTagPattern, tag), members)
+}
+
+func (t bigcacheTagStorage) DeleteTag(_ context.Context, tag string) error {
+	err := t.store.Client.Delete(fmt.Sprintf(BigcacheTagPattern, tag))
+	if errors.Is(err, bigcache.ErrEntryNotFound) {
+		return nil
+	}
+
+	return err
+}
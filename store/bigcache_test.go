@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/allegro/bigcache/v3"
 	"github.com/prodadidb/gocache/store"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -196,8 +197,8 @@ func TestBigcacheSetWithTags(t *testing.T) {
 
 	client := NewMockBigcacheClientInterface(ctrl)
 	client.EXPECT().Set(cacheKey, cacheValue).Return(nil)
-	client.EXPECT().Get("gocache_tag_tag1").Return(nil, nil)
-	client.EXPECT().Set("gocache_tag_tag1", []byte("my-key")).Return(nil)
+	client.EXPECT().Get("gocache_tag_tag1").Return(nil, bigcache.ErrEntryNotFound)
+	client.EXPECT().Set("gocache_tag_tag1", matchTagMembers("my-key")).Return(nil)
 
 	s := store.NewBigcache(client)
 
@@ -219,8 +220,8 @@ func TestBigcacheSetWithTagsWhenAlreadyInserted(t *testing.T) {
 
 	client := NewMockBigcacheClientInterface(ctrl)
 	client.EXPECT().Set(cacheKey, cacheValue).Return(nil)
-	client.EXPECT().Get("gocache_tag_tag1").Return([]byte("my-key,a-second-key"), nil)
-	client.EXPECT().Set("gocache_tag_tag1", []byte("my-key,a-second-key")).Return(nil)
+	client.EXPECT().Get("gocache_tag_tag1").Return(tagMembers("my-key", "a-second-key"), nil)
+	client.EXPECT().Set("gocache_tag_tag1", matchTagMembers("my-key", "a-second-key")).Return(nil)
 
 	s := store.NewBigcache(client)
 
@@ -279,12 +280,13 @@ func TestBigcacheInvalidate(t *testing.T) {
 
 	ctx := context.Background()
 
-	cacheKeys := []byte("a23fdf987h2svc23,jHG2372x38hf74")
+	cacheKeys := tagMembers("a23fdf987h2svc23", "jHG2372x38hf74")
 
 	client := NewMockBigcacheClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, nil)
 	client.EXPECT().Delete("a23fdf987h2svc23").Return(nil)
 	client.EXPECT().Delete("jHG2372x38hf74").Return(nil)
+	client.EXPECT().Set("gocache_tag_tag1", tagMembers()).Return(nil)
 
 	s := store.NewBigcache(client)
 
@@ -303,9 +305,68 @@ func TestBigcacheInvalidateWhenError(t *testing.T) {
 
 	cacheKeys := []byte("a23fdf987h2svc23,jHG2372x38hf74")
 
+	expectedErr := errors.New("unexpected error")
+
+	client := NewMockBigcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, nil)
+	client.EXPECT().Delete("a23fdf987h2svc23").Return(expectedErr)
+	client.EXPECT().Delete("jHG2372x38hf74").Return(nil)
+	client.EXPECT().Set("gocache_tag_tag1", tagMembers()).Return(nil)
+
+	s := store.NewBigcache(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Contains(t, err.Error(), `unable to invalidate tag "tag1": unable to delete key "a23fdf987h2svc23"`)
+
+	assert.Equal(t, map[string]int{"tag1": 1}, result.RemovedByTag)
+	assert.Equal(t, []string{"a23fdf987h2svc23"}, result.Failed)
+}
+
+func TestBigcacheInvalidateWhenKeysNotFound(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockBigcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_tag_tag1").Return(tagMembers("a23fdf987h2svc23"), nil)
+	client.EXPECT().Set("gocache_tag_tag1", tagMembers()).Return(nil)
+	client.EXPECT().Delete("a23fdf987h2svc23").Return(bigcache.ErrEntryNotFound)
+	client.EXPECT().Get("gocache_tag_tag2").Return(nil, bigcache.ErrEntryNotFound)
+
+	s := store.NewBigcache(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, store.InvalidateResult{
+		RemovedByTag: map[string]int{"tag1": 0},
+		TagsNotFound: []string{"tag2"},
+	}, *result)
+}
+
+func TestBigcacheInvalidateWhenLegacyTag(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKeys := []byte("a23fdf987h2svc23,jHG2372x38hf74")
+
 	client := NewMockBigcacheClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, nil)
-	client.EXPECT().Delete("a23fdf987h2svc23").Return(errors.New("unexpected error"))
+	client.EXPECT().Set("gocache_tag_tag1", tagMembers()).Return(nil)
+	client.EXPECT().Delete("a23fdf987h2svc23").Return(nil)
 	client.EXPECT().Delete("jHG2372x38hf74").Return(nil)
 
 	s := store.NewBigcache(client)
@@ -317,6 +378,70 @@ func TestBigcacheInvalidateWhenError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestBigcacheInvalidateByPattern(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	client, err := bigcache.New(ctx, bigcache.DefaultConfig(5*time.Minute))
+	assert.Nil(t, err)
+
+	s := store.NewBigcache(client)
+
+	_ = s.Set(ctx, "user:1:profile", []byte("value-1"))
+	_ = s.Set(ctx, "user:2:profile", []byte("value-2"))
+	_ = s.Set(ctx, "user:1:settings", []byte("value-3"))
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidatePattern("user:*:profile"), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Removed)
+
+	_, err = s.Get(ctx, "user:1:profile")
+	assert.ErrorIs(t, err, bigcache.ErrEntryNotFound)
+
+	_, err = s.Get(ctx, "user:2:profile")
+	assert.ErrorIs(t, err, bigcache.ErrEntryNotFound)
+
+	value, err := s.Get(ctx, "user:1:settings")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value-3"), value)
+}
+
+func TestBigcacheSetWithTagsWhenCustomTagIndex(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockBigcacheClientInterface(ctrl)
+	client.EXPECT().Set(cacheKey, cacheValue).Return(nil)
+	client.EXPECT().Delete(cacheKey).Return(nil)
+
+	tagStorage := newFakeTagStorage()
+	tagIndex := store.NewTagIndex(tagStorage, time.Hour)
+
+	s := store.NewBigcache(client, store.WithTagIndex(tagIndex))
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, tagIndex, s.TagIndex)
+	assert.Equal(t, []string{"my-key"}, tagMemberList(t, tagStorage.values["tag1"]))
+
+	err = s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	assert.Nil(t, err)
+	assert.Equal(t, tagMembers(), tagStorage.values["tag1"])
+}
+
 func TestBigcacheClear(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -366,3 +491,23 @@ func TestBigcacheGetType(t *testing.T) {
 	// When - Then
 	assert.Equal(t, store.BigcacheType, s.GetType())
 }
+
+func TestBigcacheGetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockBigcacheClientInterface(ctrl)
+	client.EXPECT().Get("key-1").Return([]byte("value-1"), nil)
+	client.EXPECT().Get("key-2").Return(nil, bigcache.ErrEntryNotFound)
+
+	s := store.NewBigcache(client)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": []byte("value-1")}, values)
+}
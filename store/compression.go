@@ -0,0 +1,475 @@
+package store
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCompressionThreshold is the default size (in bytes) from which
+	// values are compressed
+	DefaultCompressionThreshold = 1024
+	// DefaultMaxDecompressedSize is the default maximum size (in bytes) of a
+	// decompressed value
+	DefaultMaxDecompressedSize = 64 << 20
+
+	// CompressionNone identifies values stored without compression
+	CompressionNone byte = 0x00
+	// CompressionGzip identifies values compressed using gzip
+	CompressionGzip byte = 0x01
+	// CompressionFlate identifies values compressed using flate
+	CompressionFlate byte = 0x02
+)
+
+// compressionHeader prefixes values written by a CompressionStore, followed
+// by the version of their format and the identifier of the compression
+// algorithm. Like the headers of the other store wrappers, it starts with a
+// zero byte so that it cannot be mistaken for a text value.
+var compressionHeader = []byte("\x00gcz")
+
+// compressionFormatVersion is the version of the format of the values written
+// by a CompressionStore
+const compressionFormatVersion byte = 0x01
+
+var (
+	// ErrUnknownCompression is returned when reading a value compressed with
+	// an algorithm that has no registered compressor
+	ErrUnknownCompression = errors.New("unknown compression algorithm")
+	// ErrUnknownCompressionFormat is returned when reading a value written
+	// using a format version this store doesn't know (by a newer version)
+	ErrUnknownCompressionFormat = errors.New("unknown compression format version")
+	// ErrDecompressedSizeExceeded is returned when reading a value larger
+	// than the maximum decompressed size once decompressed
+	ErrDecompressedSizeExceeded = errors.New("decompressed value exceeds the maximum size")
+)
+
+// Compressor represents a compression algorithm. Decompress returns
+// ErrDecompressedSizeExceeded without decompressing the whole data when it is
+// larger than maxSize bytes once decompressed, maxSize being unlimited when
+// zero.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte, maxSize int) ([]byte, error)
+	ID() byte
+}
+
+// GzipCompressor compresses values using compress/gzip. A zero level uses
+// the default compression level.
+type GzipCompressor struct {
+	Level int
+}
+
+func (c GzipCompressor) Compress(data []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := gzip.NewWriterLevel(buffer, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c GzipCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return readAllLimited(reader, maxSize)
+}
+
+func (c GzipCompressor) ID() byte {
+	return CompressionGzip
+}
+
+// FlateCompressor compresses values using compress/flate. A zero level uses
+// the default compression level.
+type FlateCompressor struct {
+	Level int
+}
+
+func (c FlateCompressor) Compress(data []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	buffer := &bytes.Buffer{}
+	writer, err := flate.NewWriter(buffer, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c FlateCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+
+	return readAllLimited(reader, maxSize)
+}
+
+func (c FlateCompressor) ID() byte {
+	return CompressionFlate
+}
+
+// readAllLimited reads the given reader until EOF, or returns
+// ErrDecompressedSizeExceeded once more than maxSize bytes have been read
+func readAllLimited(reader io.Reader, maxSize int) ([]byte, error) {
+	if maxSize <= 0 {
+		return io.ReadAll(reader)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, ErrDecompressedSizeExceeded
+	}
+
+	return data, nil
+}
+
+// CompressionStats allows to returns some statistics of compression usage
+type CompressionStats struct {
+	Compressed   int
+	Uncompressed int
+	BytesIn      int64
+	BytesOut     int64
+}
+
+// Ratio returns the compression ratio achieved on compressed values, as the
+// original size divided by the compressed size
+func (s *CompressionStats) Ratio() float64 {
+	if s.BytesOut == 0 {
+		return 0
+	}
+	return float64(s.BytesIn) / float64(s.BytesOut)
+}
+
+// CompressionStatsProvider is implemented by stores reporting compression
+// statistics
+type CompressionStatsProvider interface {
+	GetCompressionStats() *CompressionStats
+}
+
+// CompressionOption represents a compression store option function.
+type CompressionOption func(o *CompressionOptions)
+
+type CompressionOptions struct {
+	Compressor          Compressor
+	Threshold           int
+	MaxDecompressedSize int
+}
+
+func applyCompressionOptions(opts ...CompressionOption) *CompressionOptions {
+	o := &CompressionOptions{
+		Compressor:          GzipCompressor{},
+		Threshold:           DefaultCompressionThreshold,
+		MaxDecompressedSize: DefaultMaxDecompressedSize,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithCompressor allows to specify the algorithm used to compress values.
+// Defaults to gzip.
+func WithCompressor(compressor Compressor) CompressionOption {
+	return func(o *CompressionOptions) {
+		o.Compressor = compressor
+	}
+}
+
+// WithCompressionThreshold allows to specify the size (in bytes) from which
+// values are compressed.
+func WithCompressionThreshold(threshold int) CompressionOption {
+	return func(o *CompressionOptions) {
+		o.Threshold = threshold
+	}
+}
+
+// WithMaxDecompressedSize allows to specify the maximum size (in bytes) of a
+// value once decompressed, so that a small corrupted or forged value can't
+// exhaust the memory when read. Values larger than it are written without
+// compression. Defaults to DefaultMaxDecompressedSize, a zero value means no
+// limit.
+func WithMaxDecompressedSize(size int) CompressionOption {
+	return func(o *CompressionOptions) {
+		o.MaxDecompressedSize = size
+	}
+}
+
+// CompressionStore is a store wrapper that compresses []byte and string values
+// above a size threshold. Values are written along with a header identifying
+// the version of their format and the compression algorithm (or its absence),
+// and values written without header are returned as is.
+type CompressionStore struct {
+	Store   StoreInterface
+	Options *CompressionOptions
+
+	compressors map[byte]Compressor
+	stats       *CompressionStats
+	statsMtx    sync.Mutex
+}
+
+// NewCompression creates a new store wrapper compressing the values of the
+// given store
+func NewCompression(store StoreInterface, options ...CompressionOption) *CompressionStore {
+	s := &CompressionStore{
+		Store:   store,
+		Options: applyCompressionOptions(options...),
+		compressors: map[byte]Compressor{
+			CompressionGzip:  GzipCompressor{},
+			CompressionFlate: FlateCompressor{},
+		},
+		stats: &CompressionStats{},
+	}
+
+	s.compressors[s.Options.Compressor.ID()] = s.Options.Compressor
+
+	return s
+}
+
+// Get returns data stored from a given key
+func (s *CompressionStore) Get(ctx context.Context, key any) (any, error) {
+	value, err := s.Store.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+
+	return s.decode(value)
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *CompressionStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, ttl, err := s.Store.GetWithTTL(ctx, key)
+	if err != nil {
+		return value, ttl, err
+	}
+
+	value, err = s.decode(value)
+	return value, ttl, err
+}
+
+// GetTags returns the tags the value of the given key has been set with, when
+// the wrapped store keeps them
+func (s *CompressionStore) GetTags(ctx context.Context, key any) ([]string, error) {
+	return GetTags(ctx, s.Store, key)
+}
+
+// GetMany returns data stored from given keys
+func (s *CompressionStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values, err := s.Store.GetMany(ctx, keys)
+
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for key, value := range values {
+		decoded, err := s.decode(value)
+		if err != nil {
+			delete(values, key)
+			errs = append(errs, err)
+			continue
+		}
+		values[key] = decoded
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// Set defines data in the wrapped store for given key identifier
+func (s *CompressionStore) Set(ctx context.Context, key any, value any, options ...Option) error {
+	encoded, err := s.encode(value)
+	if err != nil {
+		return err
+	}
+
+	return s.Store.Set(ctx, key, encoded, options...)
+}
+
+// SetMany defines data in the wrapped store for given key identifiers
+func (s *CompressionStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	encodedItems := make(map[any]any, len(items))
+	for key, value := range items {
+		encoded, err := s.encode(value)
+		if err != nil {
+			return err
+		}
+		encodedItems[key] = encoded
+	}
+
+	return s.Store.SetMany(ctx, encodedItems, options...)
+}
+
+// Delete removes data from the wrapped store for given key identifier
+func (s *CompressionStore) Delete(ctx context.Context, key any) error {
+	return s.Store.Delete(ctx, key)
+}
+
+// DeleteMany removes data from the wrapped store for given key identifiers
+func (s *CompressionStore) DeleteMany(ctx context.Context, keys []any) error {
+	return s.Store.DeleteMany(ctx, keys)
+}
+
+// Invalidate invalidates some cache data in the wrapped store for given options
+func (s *CompressionStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
+	return s.Store.Invalidate(ctx, options...)
+}
+
+// Clear resets all data in the wrapped store
+func (s *CompressionStore) Clear(ctx context.Context) error {
+	return s.Store.Clear(ctx)
+}
+
+// GetType returns the type of the wrapped store
+func (s *CompressionStore) GetType() string {
+	return s.Store.GetType()
+}
+
+// GetCompressionStats returns some statistics about the compressed values
+func (s *CompressionStore) GetCompressionStats() *CompressionStats {
+	s.statsMtx.Lock()
+	defer s.statsMtx.Unlock()
+	stats := *s.stats
+	return &stats
+}
+
+// encode compresses the given value when it is large enough, and prefixes it
+// with the compression header. Values other than []byte and string are
+// returned as is.
+func (s *CompressionStore) encode(value any) (any, error) {
+	switch v := value.(type) {
+	case []byte:
+		return s.encodeBytes(v)
+	case string:
+		encoded, err := s.encodeBytes([]byte(v))
+		return string(encoded), err
+	}
+
+	return value, nil
+}
+
+func (s *CompressionStore) encodeBytes(data []byte) ([]byte, error) {
+	compressorID := CompressionNone
+	payload := data
+
+	if len(data) >= s.Options.Threshold && !s.exceedsMaxSize(len(data)) {
+		compressed, err := s.Options.Compressor.Compress(data)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep the value uncompressed when compression does not save space
+		if len(compressed) < len(data) {
+			compressorID = s.Options.Compressor.ID()
+			payload = compressed
+		}
+	}
+
+	s.statsMtx.Lock()
+	if compressorID == CompressionNone {
+		s.stats.Uncompressed++
+	} else {
+		s.stats.Compressed++
+		s.stats.BytesIn += int64(len(data))
+		s.stats.BytesOut += int64(len(payload))
+	}
+	s.statsMtx.Unlock()
+
+	result := make([]byte, 0, len(compressionHeader)+2+len(payload))
+	result = append(result, compressionHeader...)
+	result = append(result, compressionFormatVersion, compressorID)
+
+	return append(result, payload...), nil
+}
+
+// decode decompresses the given value when it has been written with the
+// compression header. Values are returned with their original type.
+func (s *CompressionStore) decode(value any) (any, error) {
+	switch v := value.(type) {
+	case []byte:
+		return s.decodeBytes(v)
+	case string:
+		decoded, err := s.decodeBytes([]byte(v))
+		if err != nil {
+			return nil, err
+		}
+		return string(decoded), nil
+	}
+
+	return value, nil
+}
+
+func (s *CompressionStore) decodeBytes(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressionHeader) || len(data) <= len(compressionHeader) {
+		return data, nil
+	}
+
+	version := data[len(compressionHeader)]
+	if version != compressionFormatVersion {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnknownCompressionFormat, version)
+	}
+	if len(data) < len(compressionHeader)+2 {
+		return nil, fmt.Errorf("%w: missing compression algorithm", ErrUnknownCompression)
+	}
+
+	compressorID := data[len(compressionHeader)+1]
+	payload := data[len(compressionHeader)+2:]
+
+	if compressorID == CompressionNone {
+		return payload, nil
+	}
+
+	compressor, ok := s.compressors[compressorID]
+	if !ok {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnknownCompression, compressorID)
+	}
+
+	decompressed, err := compressor.Decompress(payload, s.Options.MaxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	// Compressors may not honor the maximum size while decompressing
+	if s.exceedsMaxSize(len(decompressed)) {
+		return nil, ErrDecompressedSizeExceeded
+	}
+
+	return decompressed, nil
+}
+
+func (s *CompressionStore) exceedsMaxSize(size int) bool {
+	return s.Options.MaxDecompressedSize > 0 && size > s.Options.MaxDecompressedSize
+}
@@ -0,0 +1,244 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewCompression(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().GetType().Return("redis")
+
+	// When
+	compressionStore := store.NewCompression(s, store.WithCompressionThreshold(10))
+
+	// Then
+	assert.IsType(t, new(store.CompressionStore), compressionStore)
+	assert.Equal(t, s, compressionStore.Store)
+	assert.Equal(t, 10, compressionStore.Options.Threshold)
+	assert.Equal(t, store.GzipCompressor{}, compressionStore.Options.Compressor)
+	assert.Equal(t, "redis", compressionStore.GetType())
+}
+
+func TestCompressionSetWhenAboveThreshold(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore, store.WithCompressionThreshold(10))
+
+	cacheValue := []byte(strings.Repeat("my-cache-value", 100))
+
+	// When
+	err := s.Set(ctx, "my-key", cacheValue, store.WithExpiration(10*time.Second))
+
+	// Then
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gcz\x01\x01")))
+	assert.Less(t, len(stored.([]byte)), len(cacheValue))
+
+	value, ttl, err := s.GetWithTTL(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+	assert.True(t, ttl > 9*time.Second)
+
+	stats := s.GetCompressionStats()
+	assert.Equal(t, 1, stats.Compressed)
+	assert.Equal(t, 0, stats.Uncompressed)
+	assert.Equal(t, int64(len(cacheValue)), stats.BytesIn)
+	assert.Greater(t, stats.Ratio(), float64(1))
+}
+
+func TestCompressionSetWhenBelowThreshold(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore)
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value")
+
+	// Then
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "\x00gcz\x01\x00my-cache-value", stored)
+
+	value, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cache-value", value)
+
+	assert.Equal(t, 1, s.GetCompressionStats().Uncompressed)
+}
+
+func TestCompressionGetWhenStringStoreAndFlate(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	writer := store.NewCompression(memoryStore, store.WithCompressor(store.FlateCompressor{}), store.WithCompressionThreshold(10))
+	reader := store.NewCompression(memoryStore)
+
+	cacheValue := strings.Repeat("my-cache-value", 100)
+
+	err := writer.Set(ctx, "my-key", cacheValue)
+	assert.Nil(t, err)
+
+	// When
+	value, err := reader.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestCompressionGetWhenLegacyValue(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore)
+
+	err := memoryStore.Set(ctx, "my-key", []byte("my-legacy-value"))
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("my-legacy-value"), value)
+}
+
+func TestCompressionGetWhenUnknownCompression(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore)
+
+	err := memoryStore.Set(ctx, "my-key", []byte("\x00gcz\x01\x7fdata"))
+	assert.Nil(t, err)
+
+	// When
+	_, err = s.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrUnknownCompression)
+}
+
+func TestCompressionGetWhenUnknownFormatVersion(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore)
+
+	err := memoryStore.Set(ctx, "my-key", []byte("\x00gcz\x02\x01data"))
+	assert.Nil(t, err)
+
+	// When
+	_, err = s.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrUnknownCompressionFormat)
+}
+
+func TestCompressionGetWhenDecompressedSizeExceeded(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	writer := store.NewCompression(memoryStore, store.WithMaxDecompressedSize(0))
+	reader := store.NewCompression(memoryStore, store.WithMaxDecompressedSize(1000))
+
+	err := writer.Set(ctx, "my-key", []byte(strings.Repeat("my-cache-value", 100)))
+	assert.Nil(t, err)
+
+	// When
+	_, err = reader.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrDecompressedSizeExceeded)
+}
+
+func TestCompressionSetWhenAboveMaxDecompressedSize(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewCompression(memoryStore, store.WithMaxDecompressedSize(1000))
+
+	cacheValue := []byte(strings.Repeat("my-cache-value", 100))
+
+	// When
+	err := s.Set(ctx, "my-key", cacheValue)
+
+	// Then
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gcz\x01\x00")))
+
+	value, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestCompressionGetMany(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewCompression(store.NewMemory(), store.WithCompressionThreshold(10))
+
+	items := map[any]any{
+		"key-1": []byte(strings.Repeat("value-1", 100)),
+		"key-2": []byte("value-2"),
+	}
+
+	err := s.SetMany(ctx, items)
+	assert.Nil(t, err)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, items, values)
+}
+
+func TestCompressionSetWhenNotBytes(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := map[string]struct{}{"my-key": {}}
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Set(ctx, "my-key", cacheValue).Return(nil)
+
+	compressionStore := store.NewCompression(s)
+
+	// When
+	err := compressionStore.Set(ctx, "my-key", cacheValue)
+
+	// Then
+	assert.Nil(t, err)
+}
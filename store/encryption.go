@@ -0,0 +1,337 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// encryptionHeader prefixes values written by an EncryptionStore, followed by
+// the length of the key ID, the key ID and the nonce.
+var encryptionHeader = []byte("\x00gce\x01")
+
+var (
+	// ErrDecryptionFailed is returned when a value cannot be decrypted: it has
+	// not been encrypted, it has been tampered with or its key is unknown
+	ErrDecryptionFailed = errors.New("unable to decrypt value")
+	// ErrUnencryptableValue is returned when setting a value that is neither a
+	// []byte nor a string
+	ErrUnencryptableValue = errors.New("only []byte and string values can be encrypted")
+	// ErrInvalidKeyID is returned when a key ID is empty or too long
+	ErrInvalidKeyID = errors.New("key ID must be between 1 and 255 bytes long")
+)
+
+// KeyProvider provides the keys used to encrypt and decrypt values. Keys are
+// identified by an ID written along with encrypted values, so that values
+// encrypted with a previous key can still be read after a key rotation.
+// Keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+type KeyProvider interface {
+	// CurrentKey returns the key used to encrypt new values, along with its ID
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	// Key returns the key of the given ID
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider holding a fixed set of keys
+type StaticKeyProvider struct {
+	CurrentKeyID string
+	Keys         map[string][]byte
+}
+
+// NewStaticKeyProvider creates a key provider encrypting values with the key of
+// the given ID, and able to decrypt values encrypted with any of the given keys
+func NewStaticKeyProvider(currentKeyID string, keys map[string][]byte) *StaticKeyProvider {
+	return &StaticKeyProvider{
+		CurrentKeyID: currentKeyID,
+		Keys:         keys,
+	}
+}
+
+func (p *StaticKeyProvider) CurrentKey(ctx context.Context) (string, []byte, error) {
+	key, err := p.Key(ctx, p.CurrentKeyID)
+	return p.CurrentKeyID, key, err
+}
+
+func (p *StaticKeyProvider) Key(_ context.Context, keyID string) ([]byte, error) {
+	key, ok := p.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", keyID)
+	}
+	return key, nil
+}
+
+// EncryptionOption represents an encryption store option function.
+type EncryptionOption func(o *EncryptionOptions)
+
+type EncryptionOptions struct {
+	DecryptionFailureAsMiss bool
+}
+
+func applyEncryptionOptions(opts ...EncryptionOption) *EncryptionOptions {
+	o := &EncryptionOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithDecryptionFailureAsMiss allows to return values that cannot be decrypted
+// as not found (a NotFound error wrapping ErrDecryptionFailed) instead of an
+// ErrDecryptionFailed error, so that caches load them again.
+func WithDecryptionFailureAsMiss() EncryptionOption {
+	return func(o *EncryptionOptions) {
+		o.DecryptionFailureAsMiss = true
+	}
+}
+
+// EncryptionStore is a store wrapper that encrypts values using AES-GCM before
+// writing them in the wrapped store. The cache key is authenticated along
+// with the value, so that an encrypted value cannot be moved to another key.
+type EncryptionStore struct {
+	Store       StoreInterface
+	KeyProvider KeyProvider
+	Options     *EncryptionOptions
+}
+
+// NewEncryption creates a new store wrapper encrypting the values of the given
+// store with the keys of the given provider
+func NewEncryption(store StoreInterface, keyProvider KeyProvider, options ...EncryptionOption) *EncryptionStore {
+	return &EncryptionStore{
+		Store:       store,
+		KeyProvider: keyProvider,
+		Options:     applyEncryptionOptions(options...),
+	}
+}
+
+// Get returns data stored from a given key
+func (s *EncryptionStore) Get(ctx context.Context, key any) (any, error) {
+	value, err := s.Store.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+
+	return s.decrypt(ctx, key, value)
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *EncryptionStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	value, ttl, err := s.Store.GetWithTTL(ctx, key)
+	if err != nil {
+		return value, ttl, err
+	}
+
+	value, err = s.decrypt(ctx, key, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return value, ttl, nil
+}
+
+// GetTags returns the tags the value of the given key has been set with, when
+// the wrapped store keeps them
+func (s *EncryptionStore) GetTags(ctx context.Context, key any) ([]string, error) {
+	return GetTags(ctx, s.Store, key)
+}
+
+// GetMany returns data stored from given keys. Values that cannot be
+// decrypted are missing from the returned map.
+func (s *EncryptionStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values, err := s.Store.GetMany(ctx, keys)
+
+	errs := []error{}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for key, value := range values {
+		decrypted, err := s.decrypt(ctx, key, value)
+		if err != nil {
+			delete(values, key)
+			if !errors.Is(err, NotFound{}) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		values[key] = decrypted
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// Set defines data in the wrapped store for given key identifier
+func (s *EncryptionStore) Set(ctx context.Context, key any, value any, options ...Option) error {
+	encrypted, err := s.encrypt(ctx, key, value)
+	if err != nil {
+		return err
+	}
+
+	return s.Store.Set(ctx, key, encrypted, options...)
+}
+
+// SetMany defines data in the wrapped store for given key identifiers
+func (s *EncryptionStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	encryptedItems := make(map[any]any, len(items))
+	for key, value := range items {
+		encrypted, err := s.encrypt(ctx, key, value)
+		if err != nil {
+			return err
+		}
+		encryptedItems[key] = encrypted
+	}
+
+	return s.Store.SetMany(ctx, encryptedItems, options...)
+}
+
+// Delete removes data from the wrapped store for given key identifier
+func (s *EncryptionStore) Delete(ctx context.Context, key any) error {
+	return s.Store.Delete(ctx, key)
+}
+
+// DeleteMany removes data from the wrapped store for given key identifiers
+func (s *EncryptionStore) DeleteMany(ctx context.Context, keys []any) error {
+	return s.Store.DeleteMany(ctx, keys)
+}
+
+// Invalidate invalidates some cache data in the wrapped store for given options
+func (s *EncryptionStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
+	return s.Store.Invalidate(ctx, options...)
+}
+
+// Clear resets all data in the wrapped store
+func (s *EncryptionStore) Clear(ctx context.Context) error {
+	return s.Store.Clear(ctx)
+}
+
+// GetType returns the type of the wrapped store
+func (s *EncryptionStore) GetType() string {
+	return s.Store.GetType()
+}
+
+// encrypt returns the given value encrypted with the current key, with the
+// same type as the given value
+func (s *EncryptionStore) encrypt(ctx context.Context, key any, value any) (any, error) {
+	switch v := value.(type) {
+	case []byte:
+		return s.encryptBytes(ctx, key, v)
+	case string:
+		encrypted, err := s.encryptBytes(ctx, key, []byte(v))
+		return string(encrypted), err
+	}
+
+	return nil, ErrUnencryptableValue
+}
+
+func (s *EncryptionStore) encryptBytes(ctx context.Context, key any, plaintext []byte) ([]byte, error) {
+	keyID, encryptionKey, err := s.KeyProvider.CurrentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyID) == 0 || len(keyID) > 255 {
+		return nil, ErrInvalidKeyID
+	}
+
+	aead, err := newAEAD(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(encryptionHeader)+1+len(keyID)+aead.NonceSize())
+	header = append(header, encryptionHeader...)
+	header = append(header, byte(len(keyID)))
+	header = append(header, keyID...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	result := append(header, nonce...)
+
+	return aead.Seal(result, nonce, plaintext, additionalData(header, key)), nil
+}
+
+// decrypt returns the given value decrypted, with the same type as the given
+// value
+func (s *EncryptionStore) decrypt(ctx context.Context, key any, value any) (any, error) {
+	var (
+		plaintext []byte
+		err       error
+	)
+
+	switch v := value.(type) {
+	case []byte:
+		plaintext, err = s.decryptBytes(ctx, key, v)
+		value = plaintext
+	case string:
+		plaintext, err = s.decryptBytes(ctx, key, []byte(v))
+		value = string(plaintext)
+	default:
+		err = ErrDecryptionFailed
+	}
+
+	if err != nil {
+		if s.Options.DecryptionFailureAsMiss {
+			return nil, NotFoundWithCause(err)
+		}
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (s *EncryptionStore) decryptBytes(ctx context.Context, key any, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptionHeader) || len(data) <= len(encryptionHeader) {
+		return nil, fmt.Errorf("%w: missing encryption header", ErrDecryptionFailed)
+	}
+
+	keyIDEnd := len(encryptionHeader) + 1 + int(data[len(encryptionHeader)])
+	if len(data) < keyIDEnd {
+		return nil, fmt.Errorf("%w: truncated header", ErrDecryptionFailed)
+	}
+	keyID := string(data[len(encryptionHeader)+1 : keyIDEnd])
+
+	decryptionKey, err := s.KeyProvider.Key(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	aead, err := newAEAD(decryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	nonceEnd := keyIDEnd + aead.NonceSize()
+	if len(data) < nonceEnd {
+		return nil, fmt.Errorf("%w: truncated header", ErrDecryptionFailed)
+	}
+
+	plaintext, err := aead.Open(nil, data[keyIDEnd:nonceEnd], data[nonceEnd:], additionalData(data[:keyIDEnd], key))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData returns the data authenticated along with a value: its
+// header and its cache key
+func additionalData(header []byte, key any) []byte {
+	return append(append([]byte{}, header...), fmt.Sprint(key)...)
+}
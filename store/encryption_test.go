@@ -0,0 +1,266 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	encryptionKey1 = []byte("0123456789abcdef0123456789abcdef")
+	encryptionKey2 = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestNewEncryption(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().GetType().Return("redis")
+
+	keyProvider := store.NewStaticKeyProvider("key-1", map[string][]byte{"key-1": encryptionKey1})
+
+	// When
+	encryptionStore := store.NewEncryption(s, keyProvider, store.WithDecryptionFailureAsMiss())
+
+	// Then
+	assert.IsType(t, new(store.EncryptionStore), encryptionStore)
+	assert.Equal(t, s, encryptionStore.Store)
+	assert.Equal(t, keyProvider, encryptionStore.KeyProvider)
+	assert.True(t, encryptionStore.Options.DecryptionFailureAsMiss)
+	assert.Equal(t, "redis", encryptionStore.GetType())
+}
+
+func TestEncryptionSetAndGet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	cacheValue := []byte("my-cache-value")
+
+	// When
+	err := s.Set(ctx, "my-key", cacheValue, store.WithExpiration(10*time.Second))
+
+	// Then
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gce\x01\x05key-1")))
+	assert.False(t, bytes.Contains(stored.([]byte), cacheValue))
+
+	value, ttl, err := s.GetWithTTL(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+	assert.True(t, ttl > 9*time.Second)
+}
+
+func TestEncryptionSetAndGetWhenString(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewEncryption(store.NewMemory(), store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	err := s.Set(ctx, "my-key", "my-cache-value")
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cache-value", value)
+}
+
+func TestEncryptionSetWhenUnencryptableValue(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	s := store.NewEncryption(NewMockStoreInterface(ctrl), store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	// When
+	err := s.Set(ctx, "my-key", 42)
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrUnencryptableValue)
+}
+
+func TestEncryptionGetWhenKeyRotated(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	keyProvider := store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	})
+	s := store.NewEncryption(memoryStore, keyProvider)
+
+	err := s.Set(ctx, "old-key", []byte("old-value"))
+	assert.Nil(t, err)
+
+	keyProvider.Keys["key-2"] = encryptionKey2
+	keyProvider.CurrentKeyID = "key-2"
+
+	err = s.Set(ctx, "new-key", []byte("new-value"))
+	assert.Nil(t, err)
+
+	// When
+	oldValue, oldErr := s.Get(ctx, "old-key")
+	newValue, newErr := s.Get(ctx, "new-key")
+
+	// Then
+	assert.Nil(t, oldErr)
+	assert.Equal(t, []byte("old-value"), oldValue)
+
+	assert.Nil(t, newErr)
+	assert.Equal(t, []byte("new-value"), newValue)
+
+	stored, err := memoryStore.Get(ctx, "new-key")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(stored.([]byte), []byte("\x00gce\x01\x05key-2")))
+}
+
+func TestEncryptionGetWhenTampered(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"))
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	tampered := append([]byte{}, stored.([]byte)...)
+	tampered[len(tampered)-1] ^= 0xff
+	err = memoryStore.Set(ctx, "my-key", tampered)
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, store.ErrDecryptionFailed)
+	assert.False(t, errors.Is(err, store.NotFound{}))
+}
+
+func TestEncryptionGetWhenMovedToAnotherKey(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"))
+	assert.Nil(t, err)
+
+	stored, err := memoryStore.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	err = memoryStore.Set(ctx, "other-key", stored)
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "other-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, store.ErrDecryptionFailed)
+}
+
+func TestEncryptionGetWhenUnknownKeyID(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+
+	writer := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-2", map[string][]byte{
+		"key-2": encryptionKey2,
+	}))
+	err := writer.Set(ctx, "my-key", []byte("my-cache-value"))
+	assert.Nil(t, err)
+
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}))
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, store.ErrDecryptionFailed)
+}
+
+func TestEncryptionGetWhenNotEncryptedAndFailureAsMiss(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}), store.WithDecryptionFailureAsMiss())
+
+	err := memoryStore.Set(ctx, "my-key", []byte("my-plain-value"))
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.True(t, errors.Is(err, store.NotFound{}))
+	assert.ErrorIs(t, err, store.ErrDecryptionFailed)
+}
+
+func TestEncryptionGetMany(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	s := store.NewEncryption(memoryStore, store.NewStaticKeyProvider("key-1", map[string][]byte{
+		"key-1": encryptionKey1,
+	}), store.WithDecryptionFailureAsMiss())
+
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": []byte("value-1"),
+		"key-2": []byte("value-2"),
+	})
+	assert.Nil(t, err)
+
+	err = memoryStore.Set(ctx, "key-3", []byte("my-plain-value"))
+	assert.Nil(t, err)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{
+		"key-1": []byte("value-1"),
+		"key-2": []byte("value-2"),
+	}, values)
+}
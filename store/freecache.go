@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
+
+	"github.com/coocood/freecache"
 )
 
 //go:generate mockgen -destination=./mock_store_freecache_interface_test.go -package=store_test -source=freecache.go
@@ -27,20 +28,27 @@ type FreecacheClientInterface interface {
 	Del(key []byte) (affected bool)
 	DelInt(key int64) (affected bool)
 	Clear()
+	NewIterator() *freecache.Iterator
 }
 
 // FreecacheStore is a store for freecache
 type FreecacheStore struct {
-	Client  FreecacheClientInterface
-	Options *Options
+	Client   FreecacheClientInterface
+	Options  *Options
+	TagIndex TagIndex
 }
 
 // NewFreecache creates a new store to freecache instance(s)
-func NewFreecache(client FreecacheClientInterface, options ...Option) *FreecacheStore {
-	return &FreecacheStore{
+func NewFreecache(client FreecacheClientInterface, options ...StoreOption) *FreecacheStore {
+	storeOptions := applyStoreOptions(options...)
+
+	f := &FreecacheStore{
 		Client:  client,
-		Options: applyOptions(options...),
+		Options: applyOptions(storeOptions.DefaultOptions...),
 	}
+	f.TagIndex = storeOptions.tagIndex(freecacheTagStorage{store: f})
+
+	return f
 }
 
 // Get returns data stored from a given key. It returns the value or not found error
@@ -77,6 +85,11 @@ func (f *FreecacheStore) GetWithTTL(_ context.Context, key any) (any, time.Durat
 	return nil, 0, errors.New("key type not supported by Freecache store")
 }
 
+// GetMany returns data stored from given keys
+func (f *FreecacheStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	return GetManyFallback(ctx, f, keys)
+}
+
 // Set sets a key, value and expiration for a cache entry and stores it in the cache.
 // If the key is larger than 65535 or value is larger than 1/1024 of the cache size,
 // the entry will not be written to the cache. expireSeconds <= 0 means no expire,
@@ -102,42 +115,16 @@ func (f *FreecacheStore) Set(ctx context.Context, key any, value any, options ..
 			return fmt.Errorf("size of key: %v, value: %v, err: %v", k, len(val), err)
 		}
 		if tags := opts.Tags; len(tags) > 0 {
-			f.setTags(ctx, key, tags)
+			return f.TagIndex.Add(ctx, k, opts.Expiration, tags)
 		}
 		return nil
 	}
 	return errors.New("key type not supported by Freecache store")
 }
 
-func (f *FreecacheStore) setTags(ctx context.Context, key any, tags []string) {
-	for _, tag := range tags {
-		tagKey := fmt.Sprintf(FreecacheTagPattern, tag)
-		cacheKeys := f.getCacheKeysForTag(ctx, tagKey)
-
-		alreadyInserted := false
-		for _, cacheKey := range cacheKeys {
-			if cacheKey == key.(string) {
-				alreadyInserted = true
-				break
-			}
-		}
-
-		if !alreadyInserted {
-			cacheKeys = append(cacheKeys, key.(string))
-		}
-
-		_ = f.Set(ctx, tagKey, []byte(strings.Join(cacheKeys, ",")), WithExpiration(720*time.Hour))
-	}
-}
-
-func (f *FreecacheStore) getCacheKeysForTag(ctx context.Context, tagKey string) []string {
-	cacheKeys := []string{}
-	if result, err := f.Get(ctx, tagKey); err == nil && result != nil {
-		if str, ok := result.([]byte); ok {
-			cacheKeys = strings.Split(string(str), ",")
-		}
-	}
-	return cacheKeys
+// SetMany defines data for given key identifiers
+func (f *FreecacheStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, f, items, options...)
 }
 
 // Delete deletes an item in the cache by key and returns err or nil if a delete occurred
@@ -151,30 +138,49 @@ func (f *FreecacheStore) Delete(_ context.Context, key any) error {
 	return errors.New("key type not supported by Freecache store")
 }
 
+// DeleteMany removes data for given key identifiers
+func (f *FreecacheStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, f, keys)
+}
+
 // Invalidate invalidates some cache data in freecache for given options
 func (f *FreecacheStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
+	errs := []error{}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(FreecacheTagPattern, tag)
-			cacheKeys := f.getCacheKeysForTag(ctx, tagKey)
+	for _, tag := range opts.Tags {
+		cacheKeys, err := f.TagIndex.Pop(ctx, tag)
+		if err == nil {
+			err = opts.deleteTagKeys(tag, cacheKeys, f.deleteKey)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate tag %q: %w", tag, err))
+		}
+	}
 
-			for _, cacheKey := range cacheKeys {
-				err := f.Delete(ctx, cacheKey)
-				if err != nil {
-					return err
-				}
-			}
+	if opts.matchesKeys() {
+		// Keys are deleted once iterated, as deleting them while iterating
+		// would skip some of them
+		cacheKeys := []string{}
 
-			err := f.Delete(ctx, tagKey)
-			if err != nil {
-				return err
+		iterator := f.Client.NewIterator()
+		for entry := iterator.Next(); entry != nil; entry = iterator.Next() {
+			if opts.matchKey(string(entry.Key)) {
+				cacheKeys = append(cacheKeys, string(entry.Key))
 			}
 		}
+
+		if err := opts.deleteKeys(cacheKeys, f.deleteKey); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// deleteKey deletes the given key, returning whether it existed
+func (f *FreecacheStore) deleteKey(key string) (bool, error) {
+	return f.Client.Del([]byte(key)), nil
 }
 
 // Clear resets all data in the store
@@ -187,3 +193,27 @@ func (f *FreecacheStore) Clear(_ context.Context) error {
 func (f *FreecacheStore) GetType() string {
 	return FreecacheType
 }
+
+// freecacheTagStorage stores the tag index of a FreecacheStore in Freecache
+type freecacheTagStorage struct {
+	store *FreecacheStore
+}
+
+func (t freecacheTagStorage) LoadTag(_ context.Context, tag string) ([]byte, any, error) {
+	value, err := t.store.Client.Get([]byte(fmt.Sprintf(FreecacheTagPattern, tag)))
+	if err != nil {
+		// Freecache only fails to return missing or expired entries
+		return nil, nil, nil
+	}
+
+	return value, nil, nil
+}
+
+func (t freecacheTagStorage) StoreTag(_ context.Context, tag string, members []byte, _ any, expiration time.Duration) error {
+	return t.store.Client.Set([]byte(fmt.Sprintf(FreecacheTagPattern, tag)), members, int(expiration.Seconds()))
+}
+
+func (t freecacheTagStorage) DeleteTag(_ context.Context, tag string) error {
+	t.store.Client.Del([]byte(fmt.Sprintf(FreecacheTagPattern, tag)))
+	return nil
+}
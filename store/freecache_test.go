@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/coocood/freecache"
 	"github.com/prodadidb/gocache/store"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -327,11 +328,65 @@ func TestFreecacheSetWithTags(t *testing.T) {
 	client := NewMockFreecacheClientInterface(ctrl)
 	client.EXPECT().Set([]byte(cacheKey), cacheValue, 6).Return(nil)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).MaxTimes(1).Return(nil, errors.New("value not found in store"))
-	client.EXPECT().Set([]byte("freecache_tag_tag1"), []byte("my-key"), 2592000).Return(nil)
+
+	var tagValue []byte
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), gomock.Any(), 2592000).DoAndReturn(func(_, value []byte, _ int) error {
+		tagValue = value
+		return nil
+	})
 
 	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
 	err := s.Set(ctx, cacheKey, cacheValue, store.WithExpiration(6*time.Second), store.WithTags([]string{"tag1"}))
 	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"my-key": true}, tagMemberKeys(t, tagValue))
+}
+
+func TestFreecacheSetWithTagsWhenTagTTL(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockFreecacheClientInterface(ctrl)
+	client.EXPECT().Set([]byte(cacheKey), cacheValue, 0).Return(nil)
+	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(nil, errors.New("value not found in store"))
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), matchTagMembers("my-key"), 3600).Return(nil)
+
+	s := store.NewFreecache(client, store.WithTagTTL(time.Hour))
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestFreecacheSetWithTagsWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	expectedErr := errors.New("unable to set tag")
+
+	client := NewMockFreecacheClientInterface(ctrl)
+	client.EXPECT().Set([]byte(cacheKey), cacheValue, 0).Return(nil)
+	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(nil, errors.New("value not found in store"))
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), matchTagMembers("my-key"), 2592000).Return(expectedErr)
+
+	s := store.NewFreecache(client)
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.True(t, errors.Is(err, expectedErr))
 }
 
 func TestFreecacheInvalidate(t *testing.T) {
@@ -345,7 +400,7 @@ func TestFreecacheInvalidate(t *testing.T) {
 	client := NewMockFreecacheClientInterface(ctrl)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(cacheKeys, nil)
 	client.EXPECT().Del([]byte("my-key")).Return(true)
-	client.EXPECT().Del([]byte("freecache_tag_tag1")).Return(true)
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), tagMembers(), 2592000).Return(nil)
 
 	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
 
@@ -368,12 +423,12 @@ func TestFreecacheTagsAlreadyPresent(t *testing.T) {
 	oldCacheKeys := []byte("key1,key2")
 
 	client := NewMockFreecacheClientInterface(ctrl)
-	client.EXPECT().Set([]byte(cacheKey), cacheValue, 6).Return(nil)
+	client.EXPECT().Set([]byte(cacheKey), cacheValue, 0).Return(nil)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).MaxTimes(1).Return(oldCacheKeys, nil)
-	client.EXPECT().Set([]byte("freecache_tag_tag1"), []byte("key1,key2,my-key"), 2592000).Return(nil)
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), matchTagMembers("key1", "key2", "my-key"), 2592000).Return(nil)
 
-	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
-	err := s.Set(ctx, cacheKey, cacheValue, store.WithExpiration(6*time.Second), store.WithTags([]string{"tag1"}))
+	s := store.NewFreecache(client)
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
 	assert.Nil(t, err)
 }
 
@@ -386,15 +441,15 @@ func TestFreecacheTagsRefreshTime(t *testing.T) {
 	cacheKey := "my-key"
 	cacheValue := []byte("my-cache-value")
 
-	oldCacheKeys := []byte("my-key")
+	oldCacheKeys := tagMembers("my-key")
 
 	client := NewMockFreecacheClientInterface(ctrl)
-	client.EXPECT().Set([]byte(cacheKey), cacheValue, 6).Return(nil)
+	client.EXPECT().Set([]byte(cacheKey), cacheValue, 0).Return(nil)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).MaxTimes(1).Return(oldCacheKeys, nil)
-	client.EXPECT().Set([]byte("freecache_tag_tag1"), []byte("my-key"), 2592000).Return(nil)
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), matchTagMembers("my-key"), 2592000).Return(nil)
 
-	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
-	err := s.Set(ctx, cacheKey, cacheValue, store.WithExpiration(6*time.Second), store.WithTags([]string{"tag1"}))
+	s := store.NewFreecache(client)
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
 	assert.Nil(t, err)
 }
 
@@ -404,14 +459,14 @@ func TestFreecacheInvalidateMultipleKeys(t *testing.T) {
 
 	ctx := context.Background()
 
-	cacheKeys := []byte("my-key,key1,key2")
+	cacheKeys := tagMembers("my-key", "key1", "key2")
 
 	client := NewMockFreecacheClientInterface(ctrl)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(cacheKeys, nil)
 	client.EXPECT().Del([]byte("my-key")).Return(true)
 	client.EXPECT().Del([]byte("key1")).Return(true)
 	client.EXPECT().Del([]byte("key2")).Return(true)
-	client.EXPECT().Del([]byte("freecache_tag_tag1")).Return(true)
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), tagMembers(), 2592000).Return(nil)
 
 	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
 
@@ -422,7 +477,7 @@ func TestFreecacheInvalidateMultipleKeys(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func TestFreecacheFailedInvalidateMultipleKeys(t *testing.T) {
+func TestFreecacheInvalidateWhenKeysAlreadyDeleted(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
 
@@ -432,31 +487,32 @@ func TestFreecacheFailedInvalidateMultipleKeys(t *testing.T) {
 
 	client := NewMockFreecacheClientInterface(ctrl)
 	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(cacheKeys, nil)
+	client.EXPECT().Set([]byte("freecache_tag_tag1"), tagMembers(), 2592000).Return(nil)
 	client.EXPECT().Del([]byte("my-key")).Return(false)
+	client.EXPECT().Del([]byte("key1")).Return(true)
+	client.EXPECT().Del([]byte("key2")).Return(false)
 
 	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
 
+	result := &store.InvalidateResult{}
+
 	// When
-	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
 
 	// Then
-	assert.EqualError(t, err, "failed to delete key my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Removed)
+	assert.Empty(t, result.Failed)
 }
 
-func TestFreecacheFailedInvalidatePattern(t *testing.T) {
+func TestFreecacheInvalidateWhenTagAlreadyDeleted(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
 
 	ctx := context.Background()
 
-	cacheKeys := []byte("my-key,key1,key2")
-
 	client := NewMockFreecacheClientInterface(ctrl)
-	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(cacheKeys, nil)
-	client.EXPECT().Del([]byte("my-key")).Return(true)
-	client.EXPECT().Del([]byte("key1")).Return(true)
-	client.EXPECT().Del([]byte("key2")).Return(true)
-	client.EXPECT().Del([]byte("freecache_tag_tag1")).Return(false)
+	client.EXPECT().Get([]byte("freecache_tag_tag1")).Return(nil, errors.New("value not found in store"))
 
 	s := store.NewFreecache(client, store.WithExpiration(6*time.Second))
 
@@ -464,7 +520,37 @@ func TestFreecacheFailedInvalidatePattern(t *testing.T) {
 	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
 
 	// Then
-	assert.EqualError(t, err, "failed to delete key freecache_tag_tag1")
+	assert.Nil(t, err)
+}
+
+func TestFreecacheInvalidateByPrefix(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewFreecache(freecache.NewCache(1024 * 1024))
+
+	_ = s.Set(ctx, "user:1", []byte("value-1"))
+	_ = s.Set(ctx, "user:2", []byte("value-2"))
+	_ = s.Set(ctx, "product:1", []byte("value-3"))
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePrefix("user:"), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Removed)
+
+	_, err = s.Get(ctx, "user:1")
+	assert.NotNil(t, err)
+
+	_, err = s.Get(ctx, "user:2")
+	assert.NotNil(t, err)
+
+	value, err := s.Get(ctx, "product:1")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value-3"), value)
 }
 
 func TestFreecacheClearAll(t *testing.T) {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	gocache "github.com/patrickmn/go-cache"
 )
 
 //go:generate mockgen -destination=./mock_store_go_cache_interface_test.go -package=store_test -source=go_cache.go
@@ -24,6 +26,7 @@ type GoCacheClientInterface interface {
 	Set(k string, x any, d time.Duration)
 	Delete(k string)
 	Flush()
+	Items() map[string]gocache.Item
 }
 
 // GoCacheStore is a store for GoCache (memory) library
@@ -31,13 +34,18 @@ type GoCacheStore struct {
 	mu      sync.RWMutex
 	Client  GoCacheClientInterface
 	Options *Options
+
+	storeOptions *StoreOptions
 }
 
 // NewGoCache creates a new store to GoCache (memory) library instance
-func NewGoCache(client GoCacheClientInterface, options ...Option) *GoCacheStore {
+func NewGoCache(client GoCacheClientInterface, options ...StoreOption) *GoCacheStore {
+	storeOptions := applyStoreOptions(options...)
+
 	return &GoCacheStore{
-		Client:  client,
-		Options: applyOptions(options...),
+		Client:       client,
+		Options:      applyOptions(storeOptions.DefaultOptions...),
+		storeOptions: storeOptions,
 	}
 }
 
@@ -63,6 +71,11 @@ func (s *GoCacheStore) GetWithTTL(_ context.Context, key any) (any, time.Duratio
 	return data, duration, nil
 }
 
+// GetMany returns data stored from given keys
+func (s *GoCacheStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	return GetManyFallback(ctx, s, keys)
+}
+
 // Set defines data in GoCache memoey cache for given key identifier
 func (s *GoCacheStore) Set(ctx context.Context, key any, value any, options ...Option) error {
 	opts := applyOptions(options...)
@@ -79,6 +92,11 @@ func (s *GoCacheStore) Set(ctx context.Context, key any, value any, options ...O
 	return nil
 }
 
+// SetMany defines data for given key identifiers
+func (s *GoCacheStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, s, items, options...)
+}
+
 func (s *GoCacheStore) setTags(ctx context.Context, key any, tags []string) {
 	for _, tag := range tags {
 		tagKey := fmt.Sprintf(GoCacheTagPattern, tag)
@@ -105,7 +123,7 @@ func (s *GoCacheStore) setTags(ctx context.Context, key any, tags []string) {
 		cacheKeys[key.(string)] = struct{}{}
 		s.mu.Unlock()
 
-		s.Client.Set(tagKey, cacheKeys, 720*time.Hour)
+		s.Client.Set(tagKey, cacheKeys, s.storeOptions.tagTTL())
 	}
 }
 
@@ -115,34 +133,53 @@ func (s *GoCacheStore) Delete(_ context.Context, key any) error {
 	return nil
 }
 
+// DeleteMany removes data for given key identifiers
+func (s *GoCacheStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, s, keys)
+}
+
 // Invalidate invalidates some cache data in GoCache memoey cache for given options
 func (s *GoCacheStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(GoCacheTagPattern, tag)
-			result, err := s.Get(ctx, tagKey)
-			if err != nil {
-				return nil
-			}
+	for _, tag := range opts.Tags {
+		var cacheKeys map[string]struct{}
 
-			var cacheKeys map[string]struct{}
-			if bytes, ok := result.(map[string]struct{}); ok {
-				cacheKeys = bytes
-			}
+		tagKey := fmt.Sprintf(GoCacheTagPattern, tag)
+		if result, err := s.Get(ctx, tagKey); err == nil {
+			cacheKeys, _ = result.(map[string]struct{})
+		}
 
-			s.mu.RLock()
-			for cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
+		s.mu.RLock()
+		keys := make([]string, 0, len(cacheKeys))
+		for cacheKey := range cacheKeys {
+			keys = append(keys, cacheKey)
+		}
+		_ = opts.deleteTagKeys(tag, keys, s.deleteKey)
+		s.mu.RUnlock()
+	}
+
+	if opts.matchesKeys() {
+		keys := []string{}
+		for key := range s.Client.Items() {
+			if opts.matchKey(key) {
+				keys = append(keys, key)
 			}
-			s.mu.RUnlock()
 		}
+		_ = opts.deleteKeys(keys, s.deleteKey)
 	}
 
 	return nil
 }
 
+// deleteKey deletes the given key, returning whether it existed
+func (s *GoCacheStore) deleteKey(key string) (bool, error) {
+	_, found := s.Client.Get(key)
+	s.Client.Delete(key)
+
+	return found, nil
+}
+
 // GetType returns the store type
 func (s *GoCacheStore) GetType() string {
 	return GoCacheType
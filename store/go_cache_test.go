@@ -234,7 +234,9 @@ func TestGoCacheInvalidate(t *testing.T) {
 
 	client := NewMockGoCacheClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, true)
+	client.EXPECT().Get("a23fdf987h2svc23").Return("value", true)
 	client.EXPECT().Delete("a23fdf987h2svc23")
+	client.EXPECT().Get("jHG2372x38hf74").Return("value", true)
 	client.EXPECT().Delete("jHG2372x38hf74")
 
 	s := store.NewGoCache(client)
@@ -259,11 +261,46 @@ func TestGoCacheInvalidateWhenError(t *testing.T) {
 
 	s := store.NewGoCache(client)
 
+	result := &store.InvalidateResult{}
+
 	// When
-	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
 
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, []string{"tag1"}, result.TagsNotFound)
+}
+
+func TestGoCacheInvalidateByPrefix(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	client := cache.New(10*time.Second, 30*time.Second)
+	s := store.NewGoCache(client)
+
+	_ = s.Set(ctx, "user:1", "value-1")
+	_ = s.Set(ctx, "user:2", "value-2", store.WithTags([]string{"tag1"}))
+	_ = s.Set(ctx, "product:1", "value-3")
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePrefix("user:"), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Removed)
+
+	_, err = s.Get(ctx, "user:1")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	value, err := s.Get(ctx, "product:1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-3", value)
+
+	// The tag index is left as is
+	_, found := client.Get("gocache_tag_tag1")
+	assert.True(t, found)
 }
 
 func TestGoCacheClear(t *testing.T) {
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -11,9 +12,32 @@ import (
 type StoreInterface interface {
 	Get(ctx context.Context, key any) (any, error)
 	GetWithTTL(ctx context.Context, key any) (any, time.Duration, error)
+	GetMany(ctx context.Context, keys []any) (map[any]any, error)
 	Set(ctx context.Context, key any, value any, options ...Option) error
+	SetMany(ctx context.Context, items map[any]any, options ...Option) error
 	Delete(ctx context.Context, key any) error
+	DeleteMany(ctx context.Context, keys []any) error
 	Invalidate(ctx context.Context, options ...InvalidateOption) error
 	Clear(ctx context.Context) error
 	GetType() string
 }
+
+// ErrTagsNotSupported is returned when reading the tags of a value from a
+// store which doesn't keep them along with its values
+var ErrTagsNotSupported = errors.New("store can't return the tags of its values")
+
+// TagsGetter is implemented by stores able to return the tags a value has
+// been set with
+type TagsGetter interface {
+	GetTags(ctx context.Context, key any) ([]string, error)
+}
+
+// GetTags returns the tags of the value of the given key from the given store,
+// or ErrTagsNotSupported when it doesn't implement TagsGetter
+func GetTags(ctx context.Context, store StoreInterface, key any) ([]string, error) {
+	if getter, ok := store.(TagsGetter); ok {
+		return getter.GetTags(ctx, key)
+	}
+
+	return nil, ErrTagsNotSupported
+}
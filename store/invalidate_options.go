@@ -1,10 +1,86 @@
 package store
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedInvalidateOption is returned by stores unable to invalidate
+// their values by key prefix, pattern or predicate, as they can't list their
+// keys
+var ErrUnsupportedInvalidateOption = errors.New("invalidate option not supported by store")
+
 // InvalidateOption represents a cache invalidation function.
 type InvalidateOption func(o *InvalidateOptions)
 
 type InvalidateOptions struct {
 	Tags []string
+
+	Prefix    string
+	Pattern   string
+	Predicate func(key string) bool
+
+	Result *InvalidateResult
+}
+
+// InvalidateResult reports what has been removed by an invalidation
+type InvalidateResult struct {
+	// Removed is the number of keys removed
+	Removed int
+	// RemovedByTag is the number of keys removed for each invalidated tag, a
+	// key having several of the tags being counted for the first one only
+	RemovedByTag map[string]int
+	// TagsNotFound lists the invalidated tags indexing no keys
+	TagsNotFound []string
+	// Failed lists the keys which couldn't be removed
+	Failed []string
+	// Layers is the result of each cache of a chain, in order, the other
+	// fields adding them up. A tag is then reported as not found when not
+	// found by any of the caches.
+	Layers []*InvalidateResult
+}
+
+// AddLayer adds the given result of a cache of a chain to the result
+func (r *InvalidateResult) AddLayer(layer *InvalidateResult) {
+	r.Removed += layer.Removed
+
+	for tag, count := range layer.RemovedByTag {
+		if r.RemovedByTag == nil {
+			r.RemovedByTag = map[string]int{}
+		}
+		r.RemovedByTag[tag] += count
+	}
+
+	if len(r.Layers) == 0 {
+		r.TagsNotFound = append(r.TagsNotFound, layer.TagsNotFound...)
+	} else {
+		r.TagsNotFound = intersect(r.TagsNotFound, layer.TagsNotFound)
+	}
+
+	r.Failed = append(r.Failed, layer.Failed...)
+	r.Layers = append(r.Layers, layer)
+}
+
+// intersect returns the values of a also in b, in order
+func intersect(a []string, b []string) []string {
+	values := []string{}
+	for _, value := range a {
+		for _, other := range b {
+			if value == other {
+				values = append(values, value)
+				break
+			}
+		}
+	}
+
+	return values
+}
+
+// ApplyInvalidateOptions applies the given invalidate options, so that caches
+// wrapping stores can read them
+func ApplyInvalidateOptions(opts ...InvalidateOption) *InvalidateOptions {
+	return applyInvalidateOptions(opts...)
 }
 
 func applyInvalidateOptions(opts ...InvalidateOption) *InvalidateOptions {
@@ -23,3 +99,155 @@ func WithInvalidateTags(tags []string) InvalidateOption {
 		o.Tags = tags
 	}
 }
+
+// WithInvalidatePrefix allows invalidating the keys starting with the given
+// prefix. Combined with WithInvalidatePattern or WithInvalidatePredicate,
+// keys have to match all of them.
+func WithInvalidatePrefix(prefix string) InvalidateOption {
+	return func(o *InvalidateOptions) {
+		o.Prefix = prefix
+	}
+}
+
+// WithInvalidatePattern allows invalidating the keys matching the given
+// glob-style pattern, as matched by the Redis SCAN command: "*" matches any
+// sequence of characters, "?" any character, "[abc]", "[^abc]" and "[a-c]"
+// a character of a set, and "\" escapes the next character.
+func WithInvalidatePattern(pattern string) InvalidateOption {
+	return func(o *InvalidateOptions) {
+		o.Pattern = pattern
+	}
+}
+
+// WithInvalidatePredicate allows invalidating the keys for which the given
+// function returns true. As it may be called while the store is locked, the
+// predicate must not use the store.
+func WithInvalidatePredicate(predicate func(key string) bool) InvalidateOption {
+	return func(o *InvalidateOptions) {
+		o.Predicate = predicate
+	}
+}
+
+// WithInvalidateResult allows retrieving what has been removed by the
+// invalidation, filled in by the store.
+func WithInvalidateResult(result *InvalidateResult) InvalidateOption {
+	return func(o *InvalidateOptions) {
+		o.Result = result
+	}
+}
+
+// matchesKeys returns whether keys are invalidated by prefix, pattern or
+// predicate
+func (o *InvalidateOptions) matchesKeys() bool {
+	return o.Prefix != "" || o.Pattern != "" || o.Predicate != nil
+}
+
+// matchKey returns whether the given key matches the prefix, the pattern and
+// the predicate to invalidate
+func (o *InvalidateOptions) matchKey(key string) bool {
+	if !strings.HasPrefix(key, o.Prefix) {
+		return false
+	}
+	if o.Pattern != "" && !MatchPattern(o.Pattern, key) {
+		return false
+	}
+	if o.Predicate != nil && !o.Predicate(key) {
+		return false
+	}
+
+	return true
+}
+
+// scanPattern returns a pattern matching at least the keys to invalidate, to
+// be given to stores matching keys natively
+func (o *InvalidateOptions) scanPattern() string {
+	switch {
+	case o.Pattern != "":
+		return o.Pattern
+	case o.Prefix != "":
+		return EscapePattern(o.Prefix) + "*"
+	}
+
+	return "*"
+}
+
+// removed records the given number of removed keys in the result
+func (o *InvalidateOptions) removed(count int) {
+	if o.Result != nil {
+		o.Result.Removed += count
+	}
+}
+
+// tagRemoved records the given number of keys removed for the given tag in
+// the result
+func (o *InvalidateOptions) tagRemoved(tag string, count int) {
+	if o.Result == nil {
+		return
+	}
+
+	if o.Result.RemovedByTag == nil {
+		o.Result.RemovedByTag = map[string]int{}
+	}
+	o.Result.RemovedByTag[tag] += count
+	o.Result.Removed += count
+}
+
+// tagNotFound records the given tag as indexing no keys in the result
+func (o *InvalidateOptions) tagNotFound(tag string) {
+	if o.Result != nil {
+		o.Result.TagsNotFound = append(o.Result.TagsNotFound, tag)
+	}
+}
+
+// failed records the given keys as not removed in the result
+func (o *InvalidateOptions) failed(keys ...string) {
+	if o.Result != nil {
+		o.Result.Failed = append(o.Result.Failed, keys...)
+	}
+}
+
+// deleteTagKeys deletes the given keys of the given tag one by one using the
+// given function, returning whether the key existed. The tag is recorded in
+// the result as not found when it has no keys.
+func (o *InvalidateOptions) deleteTagKeys(tag string, keys []string, del func(key string) (bool, error)) error {
+	if len(keys) == 0 {
+		o.tagNotFound(tag)
+		return nil
+	}
+
+	count, err := o.deleteEach(keys, del)
+	o.tagRemoved(tag, count)
+
+	return err
+}
+
+// deleteKeys deletes the given keys one by one using the given function,
+// returning whether the key existed
+func (o *InvalidateOptions) deleteKeys(keys []string, del func(key string) (bool, error)) error {
+	count, err := o.deleteEach(keys, del)
+	o.removed(count)
+
+	return err
+}
+
+// deleteEach deletes the given keys one by one using the given function,
+// recording the keys failing to be deleted. It returns the number of deleted
+// keys, and the errors joined.
+func (o *InvalidateOptions) deleteEach(keys []string, del func(key string) (bool, error)) (int, error) {
+	count := 0
+	errs := []error{}
+
+	for _, key := range keys {
+		deleted, err := del(key)
+		if err != nil {
+			o.failed(key)
+			errs = append(errs, fmt.Errorf("unable to delete key %q: %w", key, err))
+			continue
+		}
+		if deleted {
+			count++
+		}
+	}
+
+	return count, errors.Join(errs...)
+}
@@ -16,3 +16,33 @@ func TestInvalidateOptionsTagsValue(t *testing.T) {
 	// When - Then
 	assert.Equal(t, []string{"tag1", "tag2", "tag3"}, options.Tags)
 }
+
+func TestInvalidateResultAddLayer(t *testing.T) {
+	// Given
+	layer1 := &store.InvalidateResult{
+		Removed:      2,
+		RemovedByTag: map[string]int{"tag1": 2},
+		TagsNotFound: []string{"tag2", "tag3"},
+		Failed:       []string{"key-1"},
+	}
+	layer2 := &store.InvalidateResult{
+		Removed:      3,
+		RemovedByTag: map[string]int{"tag1": 1, "tag2": 2},
+		TagsNotFound: []string{"tag3"},
+	}
+
+	result := &store.InvalidateResult{}
+
+	// When
+	result.AddLayer(layer1)
+	result.AddLayer(layer2)
+
+	// Then
+	assert.Equal(t, &store.InvalidateResult{
+		Removed:      5,
+		RemovedByTag: map[string]int{"tag1": 3, "tag2": 2},
+		TagsNotFound: []string{"tag3"},
+		Failed:       []string{"key-1"},
+		Layers:       []*store.InvalidateResult{layer1, layer2},
+	}, result)
+}
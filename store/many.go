@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// GetManyFallback returns the values of the given keys by getting them one by
+// one from the given store. It can be used by stores that do not support
+// multi-key operations natively. Keys that are not found are missing from the
+// returned map and other errors are returned along with the found values.
+func GetManyFallback(ctx context.Context, s StoreInterface, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	errs := []error{}
+
+	for _, key := range keys {
+		value, err := s.Get(ctx, key)
+		if errors.Is(err, NotFound{}) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		values[key] = value
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// SetManyFallback sets the given values one by one in the given store
+func SetManyFallback(ctx context.Context, s StoreInterface, items map[any]any, options ...Option) error {
+	errs := []error{}
+
+	for key, value := range items {
+		if err := s.Set(ctx, key, value, options...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeleteManyFallback removes the given keys one by one from the given store
+func DeleteManyFallback(ctx context.Context, s StoreInterface, keys []any) error {
+	errs := []error{}
+
+	for _, key := range keys {
+		if err := s.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetManyFallback(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to get key")
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Get(ctx, "key-1").Return("value-1", nil)
+	s.EXPECT().Get(ctx, "key-2").Return(nil, store.NotFound{})
+	s.EXPECT().Get(ctx, "key-3").Return(nil, expectedErr)
+
+	// When
+	values, err := store.GetManyFallback(ctx, s, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
+}
+
+func TestSetManyFallback(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to set key")
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Set(ctx, "key-1", "value-1", gomock.Any()).Return(nil)
+	s.EXPECT().Set(ctx, "key-2", "value-2", gomock.Any()).Return(expectedErr)
+
+	// When
+	err := store.SetManyFallback(ctx, s, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	}, store.WithExpiration(time.Second))
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestDeleteManyFallback(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Delete(ctx, "key-1").Return(nil)
+	s.EXPECT().Delete(ctx, "key-2").Return(nil)
+
+	// When
+	err := store.DeleteManyFallback(ctx, s, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
-	"golang.org/x/sync/errgroup"
 )
 
 //go:generate mockgen -destination=./mock_store_memcache_interface_test.go -package=store_test -source=memcache.go
@@ -16,11 +16,13 @@ import (
 // MemcacheClientInterface represents a bradfitz/gomemcache client
 type MemcacheClientInterface interface {
 	Get(key string) (item *memcache.Item, err error)
+	GetMulti(keys []string) (map[string]*memcache.Item, error)
 	Set(item *memcache.Item) error
 	Delete(item string) error
 	FlushAll() error
 	CompareAndSwap(item *memcache.Item) error
 	Add(item *memcache.Item) error
+	Increment(key string, delta uint64) (newValue uint64, err error)
 }
 
 const (
@@ -28,27 +30,60 @@ const (
 	MemcacheType = "memcache"
 	// MemcacheTagPattern represents the tag pattern to be used as a key in specified storage
 	MemcacheTagPattern = "gocache_tag_%s"
-
-	TagKeyExpiry = 720 * time.Hour
+	// MemcacheGenerationKeyPattern represents the pattern of the key holding
+	// the generation of the keys written by the store, for a clear scope
+	MemcacheGenerationKeyPattern = "gocache_generation_%s"
+
+	// TagKeyExpiry is the default time to live of the index of a tag
+	//
+	// Deprecated: use DefaultTagTTL, or WithTagTTL to change it.
+	TagKeyExpiry = DefaultTagTTL
+
+	// memcacheMaxRelativeExpiration is the longest expiration Memcache reads
+	// as a duration, longer ones being read as a Unix time
+	memcacheMaxRelativeExpiration = 30 * 24 * time.Hour
 )
 
+// ErrClearScopeRequired is returned by Clear on Memcache stores created
+// without a clear scope nor WithFlushOnClear
+var ErrClearScopeRequired = errors.New("clear requires a clear scope (see WithClearScope) or WithFlushOnClear")
+
 // MemcacheStore is a store for Memcache
 type MemcacheStore struct {
-	Client  MemcacheClientInterface
-	Options *Options
+	Client   MemcacheClientInterface
+	Options  *Options
+	TagIndex TagIndex
+
+	storeOptions *StoreOptions
+
+	// generationMtx guards the generation of the clear scope kept locally
+	generationMtx       sync.Mutex
+	generationValue     string
+	generationExpiresAt time.Time
 }
 
 // NewMemcache creates a new store to Memcache instance(s)
-func NewMemcache(client MemcacheClientInterface, options ...Option) *MemcacheStore {
-	return &MemcacheStore{
-		Client:  client,
-		Options: applyOptions(options...),
+func NewMemcache(client MemcacheClientInterface, options ...StoreOption) *MemcacheStore {
+	storeOptions := applyStoreOptions(options...)
+
+	s := &MemcacheStore{
+		Client:       client,
+		Options:      applyOptions(storeOptions.DefaultOptions...),
+		storeOptions: storeOptions,
 	}
+	s.TagIndex = storeOptions.tagIndex(memcacheTagStorage{store: s})
+
+	return s
 }
 
 // Get returns data stored from a given key
 func (s *MemcacheStore) Get(_ context.Context, key any) (any, error) {
-	item, err := s.Client.Get(key.(string))
+	generation, err := s.generation()
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.Client.Get(generationKey(generation, key.(string)))
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +96,12 @@ func (s *MemcacheStore) Get(_ context.Context, key any) (any, error) {
 
 // GetWithTTL returns data stored from a given key and its corresponding TTL
 func (s *MemcacheStore) GetWithTTL(_ context.Context, key any) (any, time.Duration, error) {
-	item, err := s.Client.Get(key.(string))
+	generation, err := s.generation()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	item, err := s.Client.Get(generationKey(generation, key.(string)))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -72,128 +112,311 @@ func (s *MemcacheStore) GetWithTTL(_ context.Context, key any) (any, time.Durati
 	return item.Value, time.Duration(item.Expiration) * time.Second, err
 }
 
+// GetMany returns data stored from given keys using a single GetMulti call
+func (s *MemcacheStore) GetMany(_ context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	generation, err := s.generation()
+	if err != nil {
+		return values, err
+	}
+
+	memcacheKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		memcacheKeys = append(memcacheKeys, generationKey(generation, key.(string)))
+	}
+
+	items, err := s.Client.GetMulti(memcacheKeys)
+	if err != nil {
+		return values, err
+	}
+
+	for i, key := range keys {
+		if item, ok := items[memcacheKeys[i]]; ok && item != nil {
+			values[key] = item.Value
+		}
+	}
+
+	return values, nil
+}
+
 // Set defines data in Memcache for given key identifier
 func (s *MemcacheStore) Set(ctx context.Context, key any, value any, options ...Option) error {
 	opts := ApplyOptionsWithDefault(s.Options, options...)
 
+	generation, err := s.generation()
+	if err != nil {
+		return err
+	}
+
 	item := &memcache.Item{
-		Key:        key.(string),
+		Key:        generationKey(generation, key.(string)),
 		Value:      value.([]byte),
-		Expiration: int32(opts.Expiration.Seconds()),
+		Expiration: memcacheExpiration(opts.Expiration),
 	}
 
-	err := s.Client.Set(item)
+	err = s.Client.Set(item)
 	if err != nil {
 		return err
 	}
 
 	if tags := opts.Tags; len(tags) > 0 {
-		s.setTags(ctx, key, tags)
+		return s.TagIndex.Add(ctx, key.(string), opts.Expiration, generationTags(generation, tags))
 	}
 
 	return nil
 }
 
-func (s *MemcacheStore) setTags(ctx context.Context, key any, tags []string) {
-	group, _ := errgroup.WithContext(ctx)
-	for _, tag := range tags {
-		currentTag := tag
-		group.Go(func() error {
-			tagKey := fmt.Sprintf(MemcacheTagPattern, currentTag)
-
-			var err error
-			for i := 0; i < 3; i++ {
-				if err = s.addKeyToTagValue(tagKey, key); err == nil {
-					return nil
-				}
-				// loop to retry any failure (including race conditions)
-			}
+// SetMany defines data in Memcache for given key identifiers
+func (s *MemcacheStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, s, items, options...)
+}
 
-			return err
-		})
+// Delete removes data from Memcache for given key identifier
+func (s *MemcacheStore) Delete(_ context.Context, key any) error {
+	generation, err := s.generation()
+	if err != nil {
+		return err
 	}
 
-	_ = group.Wait()
+	return s.Client.Delete(generationKey(generation, key.(string)))
 }
 
-func (s *MemcacheStore) addKeyToTagValue(tagKey string, key any) error {
-	var (
-		cacheKeys = []string{}
-		result    *memcache.Item
-		err       error
-	)
+// DeleteMany removes data from Memcache for given key identifiers
+func (s *MemcacheStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, s, keys)
+}
+
+// Invalidate invalidates some cache data in Memcache for given options. As
+// Memcache can't list its keys, keys can't be invalidated by prefix, pattern
+// or predicate.
+func (s *MemcacheStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
+	opts := applyInvalidateOptions(options...)
+
+	if opts.matchesKeys() {
+		return fmt.Errorf("%w: %s can't list its keys", ErrUnsupportedInvalidateOption, MemcacheType)
+	}
+
+	if len(opts.Tags) == 0 {
+		return nil
+	}
 
-	result, err = s.Client.Get(tagKey)
-	if err == nil {
-		cacheKeys = strings.Split(string(result.Value), ",")
-	} else if !errors.Is(err, memcache.ErrCacheMiss) {
+	generation, err := s.generation()
+	if err != nil {
 		return err
 	}
 
-	for _, cacheKey := range cacheKeys {
-		// if key already exists, nothing to do
-		if cacheKey == key.(string) {
-			return nil
+	errs := []error{}
+
+	for i, tag := range generationTags(generation, opts.Tags) {
+		cacheKeys, err := s.TagIndex.Pop(ctx, tag)
+		if err == nil {
+			err = opts.deleteTagKeys(opts.Tags[i], cacheKeys, func(key string) (bool, error) {
+				err := s.Client.Delete(generationKey(generation, key))
+				if errors.Is(err, memcache.ErrCacheMiss) {
+					return false, nil
+				}
+				return err == nil, err
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate tag %q: %w", opts.Tags[i], err))
 		}
 	}
 
-	cacheKeys = append(cacheKeys, key.(string))
+	return errors.Join(errs...)
+}
 
-	newVal := []byte(strings.Join(cacheKeys, ","))
+// Clear removes the data written by the store in its clear scope by
+// incrementing the generation of its keys: keys of the previous generation are
+// not read anymore and are eventually evicted by Memcache. When the store is
+// created using WithFlushOnClear, the whole Memcache server is flushed
+// instead. Without a clear scope, Clear returns ErrClearScopeRequired.
+func (s *MemcacheStore) Clear(_ context.Context) error {
+	if s.storeOptions.FlushOnClear {
+		return s.Client.FlushAll()
+	}
 
-	if result == nil {
-		// if key didnt exist, use Add to create only if still not there
-		return s.Client.Add(&memcache.Item{
-			Key:        tagKey,
-			Value:      newVal,
-			Expiration: int32(TagKeyExpiry.Seconds()),
-		})
+	if s.storeOptions.ClearScope == "" {
+		return ErrClearScopeRequired
+	}
+
+	generation, err := s.Client.Increment(fmt.Sprintf(MemcacheGenerationKeyPattern, s.storeOptions.ClearScope), 1)
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		// A missing generation is initialized with a new value on next use
+		s.resetGeneration("")
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
-	// update existing value
-	// using CompareAndSwap to ensure not to run over writes between Get and here
-	result.Value = newVal
-	result.Expiration = int32(TagKeyExpiry.Seconds())
-	return s.Client.CompareAndSwap(result)
+	s.resetGeneration(strconv.FormatUint(generation, 10))
+
+	return nil
 }
 
-// Delete removes data from Memcache for given key identifier
-func (s *MemcacheStore) Delete(_ context.Context, key any) error {
-	return s.Client.Delete(key.(string))
+// generation returns the current generation of the keys written by the store,
+// initializing it when missing. It is read again once kept locally for the
+// generation TTL. It returns an empty string when the store is created without
+// a clear scope or using WithFlushOnClear, keys being written as is.
+func (s *MemcacheStore) generation() (string, error) {
+	if s.storeOptions.ClearScope == "" || s.storeOptions.FlushOnClear {
+		return "", nil
+	}
+
+	s.generationMtx.Lock()
+	defer s.generationMtx.Unlock()
+
+	if s.generationValue != "" && time.Now().Before(s.generationExpiresAt) {
+		return s.generationValue, nil
+	}
+
+	generation, err := s.loadGeneration()
+	if err != nil {
+		return "", err
+	}
+
+	s.generationValue = generation
+	s.generationExpiresAt = time.Now().Add(s.storeOptions.generationTTL())
+
+	return generation, nil
 }
 
-// Invalidate invalidates some cache data in Memcache for given options
-func (s *MemcacheStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
-	opts := applyInvalidateOptions(options...)
+// loadGeneration reads the generation of the clear scope from Memcache,
+// initializing it when missing
+func (s *MemcacheStore) loadGeneration() (string, error) {
+	counterKey := fmt.Sprintf(MemcacheGenerationKeyPattern, s.storeOptions.ClearScope)
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(MemcacheTagPattern, tag)
-			result, err := s.Get(ctx, tagKey)
-			if err != nil {
-				return nil
-			}
-
-			cacheKeys := []string{}
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
-			}
-
-			for _, cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
-			}
+	item, err := s.Client.Get(counterKey)
+	if err == nil && item != nil {
+		return string(item.Value), nil
+	}
+	if err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+		return "", err
+	}
+
+	// The generation is initialized from the current time so that the keys
+	// of a previous generation (evicted meanwhile) are not read again
+	generation := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	err = s.Client.Add(&memcache.Item{
+		Key:   counterKey,
+		Value: []byte(generation),
+	})
+	if errors.Is(err, memcache.ErrNotStored) {
+		// Initialized concurrently by another client
+		item, err = s.Client.Get(counterKey)
+		if err != nil {
+			return "", err
 		}
+		return string(item.Value), nil
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	return generation, nil
 }
 
-// Clear resets all data in the store
-func (s *MemcacheStore) Clear(_ context.Context) error {
-	return s.Client.FlushAll()
+// resetGeneration keeps the given generation locally after a Clear, or forgets
+// it when empty so that it is read again on next use
+func (s *MemcacheStore) resetGeneration(generation string) {
+	s.generationMtx.Lock()
+	defer s.generationMtx.Unlock()
+
+	s.generationValue = generation
+	s.generationExpiresAt = time.Now().Add(s.storeOptions.generationTTL())
+}
+
+// generationKey returns the given key prefixed with the given generation
+func generationKey(generation string, key string) string {
+	if generation == "" {
+		return key
+	}
+
+	return generation + ":" + key
+}
+
+// generationTags returns the given tags prefixed with the given generation,
+// so that tags are cleared along with the keys they index
+func generationTags(generation string, tags []string) []string {
+	if generation == "" {
+		return tags
+	}
+
+	prefixed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		prefixed = append(prefixed, generationKey(generation, tag))
+	}
+
+	return prefixed
 }
 
 // GetType returns the store type
 func (s *MemcacheStore) GetType() string {
 	return MemcacheType
 }
+
+// memcacheTagStorage stores the tag index of a MemcacheStore in Memcache,
+// detecting concurrent modifications using CompareAndSwap
+type memcacheTagStorage struct {
+	store *MemcacheStore
+}
+
+func (t memcacheTagStorage) LoadTag(_ context.Context, tag string) ([]byte, any, error) {
+	item, err := t.store.Client.Get(fmt.Sprintf(MemcacheTagPattern, tag))
+	if errors.Is(err, memcache.ErrCacheMiss) || (err == nil && item == nil) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return item.Value, item, nil
+}
+
+func (t memcacheTagStorage) StoreTag(_ context.Context, tag string, members []byte, token any, expiration time.Duration) error {
+	var err error
+
+	if item, ok := token.(*memcache.Item); ok {
+		item.Value = members
+		item.Expiration = memcacheExpiration(expiration)
+		err = t.store.Client.CompareAndSwap(item)
+	} else {
+		// Add only creates the tag when still missing
+		err = t.store.Client.Add(&memcache.Item{
+			Key:        fmt.Sprintf(MemcacheTagPattern, tag),
+			Value:      members,
+			Expiration: memcacheExpiration(expiration),
+		})
+	}
+
+	if errors.Is(err, memcache.ErrCASConflict) || errors.Is(err, memcache.ErrNotStored) {
+		return ErrTagIndexConflict
+	}
+
+	return err
+}
+
+func (t memcacheTagStorage) DeleteTag(_ context.Context, tag string) error {
+	err := t.store.Client.Delete(fmt.Sprintf(MemcacheTagPattern, tag))
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil
+	}
+
+	return err
+}
+
+// memcacheExpiration returns the given expiration in seconds as read by
+// Memcache: as a Unix time when longer than 30 days
+func memcacheExpiration(expiration time.Duration) int32 {
+	if expiration > memcacheMaxRelativeExpiration {
+		return int32(time.Now().Add(expiration).Unix())
+	}
+
+	return int32(expiration.Seconds())
+}
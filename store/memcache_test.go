@@ -3,6 +3,7 @@ package store_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -12,6 +13,28 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// tagItemMatcher matches a Memcache item holding the members of a tag having
+// the given keys, whatever their expiration
+type tagItemMatcher struct {
+	key        string
+	expiration int32
+	members    gomock.Matcher
+}
+
+func matchTagItem(key string, expiration int32, keys ...string) gomock.Matcher {
+	return tagItemMatcher{key: key, expiration: expiration, members: matchTagMembers(keys...)}
+}
+
+func (m tagItemMatcher) Matches(x any) bool {
+	item, ok := x.(*memcache.Item)
+
+	return ok && item.Key == m.key && item.Expiration == m.expiration && m.members.Matches(item.Value)
+}
+
+func (m tagItemMatcher) String() string {
+	return fmt.Sprintf("item %s (expiration: %d) with %s", m.key, m.expiration, m.members)
+}
+
 func TestNewMemcache(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -171,6 +194,32 @@ func TestMemcacheSet(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMemcacheSetWhenExpirationOverThirtyDays(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expiration := 60 * 24 * time.Hour
+
+	var item *memcache.Item
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Set(gomock.Any()).DoAndReturn(func(i *memcache.Item) error {
+		item = i
+		return nil
+	})
+
+	s := store.NewMemcache(client)
+
+	// When
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"), store.WithExpiration(expiration))
+
+	// Then
+	assert.Nil(t, err)
+	assert.InDelta(t, time.Now().Add(expiration).Unix(), int64(item.Expiration), 5)
+}
+
 func TestMemcacheSetWhenNoOptionsGiven(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -237,11 +286,7 @@ func TestMemcacheSetWithTags(t *testing.T) {
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().Set(gomock.Any()).AnyTimes().Return(nil)
 	client.EXPECT().Get(tagKey).Return(nil, memcache.ErrCacheMiss)
-	client.EXPECT().Add(&memcache.Item{
-		Key:        tagKey,
-		Value:      []byte(cacheKey),
-		Expiration: int32(store.TagKeyExpiry.Seconds()),
-	}).Return(nil)
+	client.EXPECT().Add(matchTagItem(tagKey, int32(store.DefaultTagTTL.Seconds()), cacheKey)).Return(nil)
 
 	s := store.NewMemcache(client)
 
@@ -264,8 +309,10 @@ func TestMemcacheSetWithTagsWhenAlreadyInserted(t *testing.T) {
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().Set(gomock.Any()).AnyTimes().Return(nil)
 	client.EXPECT().Get("gocache_tag_tag1").Return(&memcache.Item{
+		Key:   "gocache_tag_tag1",
 		Value: []byte("my-key,a-second-key"),
 	}, nil)
+	client.EXPECT().CompareAndSwap(matchTagItem("gocache_tag_tag1", int32(store.DefaultTagTTL.Seconds()), "my-key", "a-second-key")).Return(nil)
 
 	s := store.NewMemcache(client)
 
@@ -276,6 +323,89 @@ func TestMemcacheSetWithTagsWhenAlreadyInserted(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestMemcacheSetWithTagsWhenConflict(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Set(gomock.Any()).Return(nil)
+	gomock.InOrder(
+		client.EXPECT().Get("gocache_tag_tag1").Return(nil, memcache.ErrCacheMiss),
+		client.EXPECT().Add(gomock.Any()).Return(memcache.ErrNotStored),
+		client.EXPECT().Get("gocache_tag_tag1").Return(&memcache.Item{
+			Key:   "gocache_tag_tag1",
+			Value: tagMembers("other-key"),
+		}, nil),
+		client.EXPECT().CompareAndSwap(matchTagItem("gocache_tag_tag1", int32(time.Hour.Seconds()), "other-key", cacheKey)).Return(nil),
+	)
+
+	s := store.NewMemcache(client, store.WithTagTTL(time.Hour))
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestMemcacheSetWithTagsWhenLongTagTTL(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	tagTTL := 60 * 24 * time.Hour
+
+	var tagItem *memcache.Item
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Set(gomock.Any()).Return(nil)
+	client.EXPECT().Get("gocache_tag_tag1").Return(nil, memcache.ErrCacheMiss)
+	client.EXPECT().Add(gomock.Any()).DoAndReturn(func(item *memcache.Item) error {
+		tagItem = item
+		return nil
+	})
+
+	s := store.NewMemcache(client, store.WithTagTTL(tagTTL))
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	// Memcache reads expirations longer than 30 days as a Unix time
+	assert.InDelta(t, time.Now().Add(tagTTL).Unix(), int64(tagItem.Expiration), 5)
+}
+
+func TestMemcacheSetWithTagsWhenGeneration(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil)
+	client.EXPECT().Set(&memcache.Item{Key: "42:my-key", Value: []byte("my-cache-value")}).Return(nil)
+	client.EXPECT().Get("gocache_tag_42:tag1").Return(nil, memcache.ErrCacheMiss)
+	client.EXPECT().Add(matchTagItem("gocache_tag_42:tag1", int32(store.DefaultTagTTL.Seconds()), "my-key")).Return(nil)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"), store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+}
+
 func TestMemcacheDelete(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -325,11 +455,17 @@ func TestMemcacheInvalidate(t *testing.T) {
 	ctx := context.Background()
 
 	cacheKeys := &memcache.Item{
-		Value: []byte("a23fdf987h2svc23,jHG2372x38hf74"),
+		Key:   "gocache_tag_tag1",
+		Value: tagMembers("a23fdf987h2svc23", "jHG2372x38hf74"),
 	}
 
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, nil)
+	client.EXPECT().CompareAndSwap(&memcache.Item{
+		Key:        "gocache_tag_tag1",
+		Value:      tagMembers(),
+		Expiration: int32(store.DefaultTagTTL.Seconds()),
+	}).Return(nil)
 	client.EXPECT().Delete("a23fdf987h2svc23").Return(nil)
 	client.EXPECT().Delete("jHG2372x38hf74").Return(nil)
 
@@ -349,21 +485,46 @@ func TestMemcacheInvalidateWhenError(t *testing.T) {
 	ctx := context.Background()
 
 	cacheKeys := &memcache.Item{
+		Key:   "gocache_tag_tag1",
 		Value: []byte("a23fdf987h2svc23,jHG2372x38hf74"),
 	}
 
+	expectedErr := errors.New("unexpected error")
+
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, nil)
-	client.EXPECT().Delete("a23fdf987h2svc23").Return(errors.New("unexpected error"))
+	client.EXPECT().CompareAndSwap(gomock.Any()).Return(nil)
+	client.EXPECT().Delete("a23fdf987h2svc23").Return(expectedErr)
 	client.EXPECT().Delete("jHG2372x38hf74").Return(nil)
 
 	s := store.NewMemcache(client)
 
+	result := &store.InvalidateResult{}
+
 	// When
-	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
 
 	// Then
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, 1, result.Removed)
+	assert.Equal(t, []string{"a23fdf987h2svc23"}, result.Failed)
+}
+
+func TestMemcacheInvalidateByPrefix(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+
+	s := store.NewMemcache(client)
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePrefix("user:"))
+
+	// Then
+	assert.True(t, errors.Is(err, store.ErrUnsupportedInvalidateOption))
 }
 
 func TestMemcacheClear(t *testing.T) {
@@ -375,7 +536,7 @@ func TestMemcacheClear(t *testing.T) {
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().FlushAll().Return(nil)
 
-	s := store.NewMemcache(client)
+	s := store.NewMemcache(client, store.WithFlushOnClear())
 
 	// When
 	err := s.Clear(ctx)
@@ -395,7 +556,7 @@ func TestMemcacheClearWhenError(t *testing.T) {
 	client := NewMockMemcacheClientInterface(ctrl)
 	client.EXPECT().FlushAll().Return(expectedErr)
 
-	s := store.NewMemcache(client)
+	s := store.NewMemcache(client, store.WithFlushOnClear())
 
 	// When
 	err := s.Clear(ctx)
@@ -404,6 +565,216 @@ func TestMemcacheClearWhenError(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestMemcacheGetWhenGeneration(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil)
+	client.EXPECT().Get("42:my-key").Return(&memcache.Item{Value: cacheValue}, nil)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestMemcacheSetWhenGenerationMissing(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	var generation string
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_generation_my-app").Return(nil, memcache.ErrCacheMiss)
+	client.EXPECT().Add(gomock.Any()).DoAndReturn(func(item *memcache.Item) error {
+		assert.Equal(t, "gocache_generation_my-app", item.Key)
+		generation = string(item.Value)
+		return nil
+	})
+	client.EXPECT().Set(gomock.Any()).DoAndReturn(func(item *memcache.Item) error {
+		assert.Equal(t, generation+":my-key", item.Key)
+		return nil
+	})
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"))
+
+	// Then
+	assert.Nil(t, err)
+	assert.NotEmpty(t, generation)
+}
+
+func TestMemcacheSetWhenGenerationAddedConcurrently(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Get("gocache_generation_my-app").Return(nil, memcache.ErrCacheMiss),
+		client.EXPECT().Add(gomock.Any()).Return(memcache.ErrNotStored),
+		client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil),
+	)
+	client.EXPECT().Set(&memcache.Item{Key: "42:my-key", Value: []byte("my-cache-value")}).Return(nil)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	err := s.Set(ctx, "my-key", []byte("my-cache-value"))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestMemcacheClearWhenGeneration(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Increment("gocache_generation_my-app", uint64(1)).Return(uint64(43), nil)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestMemcacheClearWhenGenerationMissing(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Increment("gocache_generation_my-app", uint64(1)).Return(uint64(0), memcache.ErrCacheMiss)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"))
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestMemcacheClearWhenNoClearScope(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+
+	s := store.NewMemcache(client)
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrClearScopeRequired)
+}
+
+func TestMemcacheGetWhenGenerationKeptLocally(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil).Times(1)
+	client.EXPECT().Get("42:my-key").Return(&memcache.Item{Value: cacheValue}, nil).Times(2)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"), store.WithGenerationTTL(time.Minute))
+
+	// When
+	_, err1 := s.Get(ctx, "my-key")
+	value, err2 := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, cacheValue, value)
+}
+
+func TestMemcacheGetWhenGenerationExpiredLocally(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil),
+		client.EXPECT().Get("42:my-key").Return(&memcache.Item{Value: []byte("value-42")}, nil),
+		client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("43")}, nil),
+		client.EXPECT().Get("43:my-key").Return(&memcache.Item{Value: []byte("value-43")}, nil),
+	)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"), store.WithGenerationTTL(10*time.Millisecond))
+
+	// When
+	_, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value-43"), value)
+}
+
+func TestMemcacheGetWhenCleared(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Get("gocache_generation_my-app").Return(&memcache.Item{Value: []byte("42")}, nil),
+		client.EXPECT().Get("42:my-key").Return(&memcache.Item{Value: []byte("my-cache-value")}, nil),
+		client.EXPECT().Increment("gocache_generation_my-app", uint64(1)).Return(uint64(43), nil),
+		client.EXPECT().Get("43:my-key").Return(nil, memcache.ErrCacheMiss),
+	)
+
+	s := store.NewMemcache(client, store.WithClearScope("my-app"), store.WithGenerationTTL(time.Minute))
+
+	// When
+	_, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+
+	err = s.Clear(ctx)
+	assert.Nil(t, err)
+
+	_, err = s.Get(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, memcache.ErrCacheMiss)
+}
+
 func TestMemcacheGetType(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -415,3 +786,24 @@ func TestMemcacheGetType(t *testing.T) {
 	// When - Then
 	assert.Equal(t, store.MemcacheType, s.GetType())
 }
+
+func TestMemcacheGetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockMemcacheClientInterface(ctrl)
+	client.EXPECT().GetMulti([]string{"key-1", "key-2"}).Return(map[string]*memcache.Item{
+		"key-1": {Key: "key-1", Value: []byte("value-1")},
+	}, nil)
+
+	s := store.NewMemcache(client)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": []byte("value-1")}, values)
+}
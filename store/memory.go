@@ -0,0 +1,439 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// MemoryType represents the storage type as a string value
+	MemoryType = "memory"
+)
+
+// ErrMemoryEntryTooLarge is returned when setting a value whose cost exceeds
+// the maximum cost of a memory store
+var ErrMemoryEntryTooLarge = errors.New("entry cost exceeds the memory store capacity")
+
+type memoryEntry struct {
+	key       string
+	value     any
+	cost      int64
+	expiresAt time.Time
+	tags      []string
+
+	// eviction policy data
+	element   *list.Element
+	frequency int
+	tick      uint64
+	index     int
+}
+
+func (e *memoryEntry) isExpired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+type memoryEviction struct {
+	entry  *memoryEntry
+	reason EvictionReason
+}
+
+// memoryUsage is the number of entries and the total cost of a memory store,
+// along with the entries being set
+type memoryUsage struct {
+	entries atomic.Int64
+	cost    atomic.Int64
+}
+
+type memoryShard struct {
+	mtx     sync.Mutex
+	entries map[string]*memoryEntry
+	tags    map[string]map[string]struct{}
+	policy  memoryPolicy
+	cost    int64
+	usage   *memoryUsage
+
+	// expiresAt is a lower bound of the expirations of the entries, zero when
+	// no entry expires, so that expired entries are only looked for once some
+	// may have expired
+	expiresAt time.Time
+}
+
+// MemoryStore is a bounded in-memory store that does not rely on any third
+// party library. Keys are spread across shards, each one having its own lock,
+// while the store bounds apply to all of them: once the store is full, the
+// shards evict their entries in turn using the configured policy.
+type MemoryStore struct {
+	Options *Options
+
+	memoryOptions *MemoryOptions
+	shards        []*memoryShard
+	seed          maphash.Seed
+	usage         *memoryUsage
+
+	// evictionMtx serializes evictions, nextShard being the shard to evict
+	// an entry from next
+	evictionMtx sync.Mutex
+	nextShard   int
+}
+
+// NewMemory creates a new in-memory store
+func NewMemory(options ...MemoryOption) *MemoryStore {
+	memoryOptions := applyMemoryOptions(options...)
+
+	s := &MemoryStore{
+		Options:       applyOptions(memoryOptions.DefaultOptions...),
+		memoryOptions: memoryOptions,
+		shards:        make([]*memoryShard, memoryOptions.Shards),
+		seed:          maphash.MakeSeed(),
+		usage:         &memoryUsage{},
+	}
+
+	for i := range s.shards {
+		s.shards[i] = &memoryShard{
+			entries: map[string]*memoryEntry{},
+			tags:    map[string]map[string]struct{}{},
+			policy:  newMemoryPolicy(memoryOptions.Policy),
+			usage:   s.usage,
+		}
+	}
+
+	return s
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	return s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+// Get returns data stored from a given key
+func (s *MemoryStore) Get(ctx context.Context, key any) (any, error) {
+	value, _, err := s.GetWithTTL(ctx, key)
+	return value, err
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *MemoryStore) GetWithTTL(_ context.Context, key any) (any, time.Duration, error) {
+	shard := s.shard(key.(string))
+	now := time.Now()
+
+	shard.mtx.Lock()
+	entry, ok := shard.entries[key.(string)]
+	if !ok {
+		shard.mtx.Unlock()
+		return nil, 0, NotFoundWithCause(errors.New("value not found in memory store"))
+	}
+
+	if entry.isExpired(now) {
+		shard.remove(entry)
+		shard.mtx.Unlock()
+
+		s.notify([]memoryEviction{{entry, EvictionReasonExpired}})
+		return nil, 0, NotFoundWithCause(errors.New("value expired in memory store"))
+	}
+
+	shard.policy.touch(entry)
+
+	var ttl time.Duration
+	if !entry.expiresAt.IsZero() {
+		ttl = entry.expiresAt.Sub(now)
+	}
+	value := entry.value
+	shard.mtx.Unlock()
+
+	return value, ttl, nil
+}
+
+// GetTags returns the tags the value of the given key has been set with
+func (s *MemoryStore) GetTags(_ context.Context, key any) ([]string, error) {
+	shard := s.shard(key.(string))
+
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	entry, ok := shard.entries[key.(string)]
+	if !ok || entry.isExpired(time.Now()) {
+		return nil, NotFoundWithCause(errors.New("value not found in memory store"))
+	}
+
+	return append([]string(nil), entry.tags...), nil
+}
+
+// GetMany returns data stored from given keys
+func (s *MemoryStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+
+	for _, key := range keys {
+		if value, err := s.Get(ctx, key); err == nil {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// Set defines data in memory for given key identifier
+func (s *MemoryStore) Set(_ context.Context, key any, value any, options ...Option) error {
+	opts := ApplyOptionsWithDefault(s.Options, options...)
+
+	entry := &memoryEntry{
+		key:   key.(string),
+		value: value,
+		cost:  opts.Cost,
+		tags:  opts.Tags,
+	}
+	if entry.cost <= 0 {
+		entry.cost = 1
+	}
+	if opts.Expiration > 0 {
+		entry.expiresAt = time.Now().Add(opts.Expiration)
+	}
+
+	if maxCost := s.memoryOptions.MaxCost; maxCost > 0 && entry.cost > maxCost {
+		return ErrMemoryEntryTooLarge
+	}
+
+	// The entry is accounted for before being added, so that concurrent
+	// sets can't fill the store beyond its bounds
+	s.usage.entries.Add(1)
+	s.usage.cost.Add(entry.cost)
+	evictions := s.makeRoom(time.Now())
+
+	shard := s.shard(entry.key)
+
+	shard.mtx.Lock()
+	if existing, ok := shard.entries[entry.key]; ok {
+		shard.remove(existing)
+	}
+	shard.add(entry)
+	shard.mtx.Unlock()
+
+	s.notify(evictions)
+
+	return nil
+}
+
+// SetMany defines data in memory for given key identifiers
+func (s *MemoryStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	for key, value := range items {
+		if err := s.Set(ctx, key, value, options...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes data in memory for given key identifier
+func (s *MemoryStore) Delete(_ context.Context, key any) error {
+	shard := s.shard(key.(string))
+
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	if entry, ok := shard.entries[key.(string)]; ok {
+		shard.remove(entry)
+	}
+
+	return nil
+}
+
+// DeleteMany removes data in memory for given key identifiers
+func (s *MemoryStore) DeleteMany(ctx context.Context, keys []any) error {
+	for _, key := range keys {
+		_ = s.Delete(ctx, key)
+	}
+
+	return nil
+}
+
+// Invalidate invalidates some cache data in memory for given options
+func (s *MemoryStore) Invalidate(_ context.Context, options ...InvalidateOption) error {
+	opts := applyInvalidateOptions(options...)
+
+	if len(opts.Tags) == 0 && !opts.matchesKeys() {
+		return nil
+	}
+
+	removedByTag := make([]int, len(opts.Tags))
+	removed := 0
+
+	for _, shard := range s.shards {
+		shard.mtx.Lock()
+		for i, tag := range opts.Tags {
+			for key := range shard.tags[tag] {
+				shard.remove(shard.entries[key])
+				removedByTag[i]++
+			}
+		}
+		if opts.matchesKeys() {
+			for key, entry := range shard.entries {
+				if opts.matchKey(key) {
+					shard.remove(entry)
+					removed++
+				}
+			}
+		}
+		shard.mtx.Unlock()
+	}
+
+	for i, tag := range opts.Tags {
+		if removedByTag[i] == 0 {
+			opts.tagNotFound(tag)
+		} else {
+			opts.tagRemoved(tag, removedByTag[i])
+		}
+	}
+	opts.removed(removed)
+
+	return nil
+}
+
+// Clear resets all data in the store
+func (s *MemoryStore) Clear(_ context.Context) error {
+	for _, shard := range s.shards {
+		shard.mtx.Lock()
+		s.usage.entries.Add(-int64(len(shard.entries)))
+		s.usage.cost.Add(-shard.cost)
+		shard.entries = map[string]*memoryEntry{}
+		shard.tags = map[string]map[string]struct{}{}
+		shard.policy = newMemoryPolicy(s.memoryOptions.Policy)
+		shard.cost = 0
+		shard.expiresAt = time.Time{}
+		shard.mtx.Unlock()
+	}
+
+	return nil
+}
+
+// GetType returns the store type
+func (s *MemoryStore) GetType() string {
+	return MemoryType
+}
+
+// makeRoom removes the expired entries, then entries chosen by the eviction
+// policy of each shard in turn, until the store fits in its bounds, and
+// returns them
+func (s *MemoryStore) makeRoom(now time.Time) []memoryEviction {
+	if !s.full() {
+		return nil
+	}
+
+	s.evictionMtx.Lock()
+	defer s.evictionMtx.Unlock()
+
+	var evictions []memoryEviction
+
+	for _, shard := range s.shards {
+		if !s.full() {
+			return evictions
+		}
+
+		shard.mtx.Lock()
+		evictions = append(evictions, shard.removeExpired(now)...)
+		shard.mtx.Unlock()
+	}
+
+	// Stop once every shard is empty: the remaining entries are still being
+	// set
+	for emptyShards := 0; s.full() && emptyShards < len(s.shards); {
+		shard := s.shards[s.nextShard]
+		s.nextShard = (s.nextShard + 1) % len(s.shards)
+
+		shard.mtx.Lock()
+		victim := shard.policy.victim()
+		if victim != nil {
+			reason := EvictionReasonCapacity
+			if victim.isExpired(now) {
+				reason = EvictionReasonExpired
+			}
+
+			shard.remove(victim)
+			evictions = append(evictions, memoryEviction{victim, reason})
+			emptyShards = 0
+		} else {
+			emptyShards++
+		}
+		shard.mtx.Unlock()
+	}
+
+	return evictions
+}
+
+// full returns whether the store exceeds its bounds
+func (s *MemoryStore) full() bool {
+	return (s.memoryOptions.MaxEntries > 0 && s.usage.entries.Load() > int64(s.memoryOptions.MaxEntries)) ||
+		(s.memoryOptions.MaxCost > 0 && s.usage.cost.Load() > s.memoryOptions.MaxCost)
+}
+
+// notify calls the eviction callback for the given evictions
+func (s *MemoryStore) notify(evictions []memoryEviction) {
+	if s.memoryOptions.OnEvict == nil {
+		return
+	}
+
+	for _, eviction := range evictions {
+		s.memoryOptions.OnEvict(eviction.entry.key, eviction.entry.value, eviction.reason)
+	}
+}
+
+// add adds the given entry to the shard, the store usage being updated by
+// the caller
+func (sh *memoryShard) add(entry *memoryEntry) {
+	sh.entries[entry.key] = entry
+	sh.cost += entry.cost
+	sh.policy.add(entry)
+
+	if !entry.expiresAt.IsZero() && (sh.expiresAt.IsZero() || entry.expiresAt.Before(sh.expiresAt)) {
+		sh.expiresAt = entry.expiresAt
+	}
+
+	for _, tag := range entry.tags {
+		keys, ok := sh.tags[tag]
+		if !ok {
+			keys = map[string]struct{}{}
+			sh.tags[tag] = keys
+		}
+		keys[entry.key] = struct{}{}
+	}
+}
+
+func (sh *memoryShard) remove(entry *memoryEntry) {
+	delete(sh.entries, entry.key)
+	sh.cost -= entry.cost
+	sh.usage.entries.Add(-1)
+	sh.usage.cost.Add(-entry.cost)
+	sh.policy.remove(entry)
+
+	for _, tag := range entry.tags {
+		delete(sh.tags[tag], entry.key)
+		if len(sh.tags[tag]) == 0 {
+			delete(sh.tags, tag)
+		}
+	}
+}
+
+// removeExpired removes the expired entries of the shard, when some may have
+// expired, and returns them
+func (sh *memoryShard) removeExpired(now time.Time) []memoryEviction {
+	if sh.expiresAt.IsZero() || now.Before(sh.expiresAt) {
+		return nil
+	}
+
+	var evictions []memoryEviction
+
+	sh.expiresAt = time.Time{}
+	for _, entry := range sh.entries {
+		if entry.isExpired(now) {
+			sh.remove(entry)
+			evictions = append(evictions, memoryEviction{entry, EvictionReasonExpired})
+		} else if !entry.expiresAt.IsZero() && (sh.expiresAt.IsZero() || entry.expiresAt.Before(sh.expiresAt)) {
+			sh.expiresAt = entry.expiresAt
+		}
+	}
+
+	return evictions
+}
@@ -0,0 +1,48 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/prodadidb/gocache/store"
+)
+
+func BenchmarkMemorySet(b *testing.B) {
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithMaxEntries(10000))
+
+	for k := 0.; k <= 10; k++ {
+		n := int(math.Pow(2, k))
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for i := 0; i < b.N*n; i++ {
+				key := fmt.Sprintf("test-%d", n)
+				value := []byte(fmt.Sprintf("value-%d", n))
+
+				_ = s.Set(ctx, key, value, store.WithTags([]string{fmt.Sprintf("tag-%d", n)}))
+			}
+		})
+	}
+}
+
+func BenchmarkMemoryGet(b *testing.B) {
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithMaxEntries(10000))
+
+	key := "test"
+	value := []byte("value")
+
+	_ = s.Set(ctx, key, value)
+
+	for k := 0.; k <= 10; k++ {
+		n := int(math.Pow(2, k))
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for i := 0; i < b.N*n; i++ {
+				_, _ = s.Get(ctx, key)
+			}
+		})
+	}
+}
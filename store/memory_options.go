@@ -0,0 +1,114 @@
+package store
+
+// EvictionPolicy represents the policy used by the memory store to choose the
+// entries to evict when it is full.
+type EvictionPolicy int
+
+const (
+	// EvictionPolicyLRU evicts the least recently used entries first
+	EvictionPolicyLRU EvictionPolicy = iota
+	// EvictionPolicyLFU evicts the least frequently used entries first
+	EvictionPolicyLFU
+)
+
+// EvictionReason represents the reason why an entry has been evicted from the
+// memory store.
+type EvictionReason int
+
+const (
+	// EvictionReasonCapacity means the entry has been evicted to make room for
+	// a new one
+	EvictionReasonCapacity EvictionReason = iota
+	// EvictionReasonExpired means the entry has been removed after its
+	// expiration
+	EvictionReasonExpired
+)
+
+// EvictionCallback is called when an entry is evicted from the memory store.
+// It is called outside of the store locks so it can use the store.
+type EvictionCallback func(key string, value any, reason EvictionReason)
+
+const (
+	// DefaultMemoryShards is the default number of shards of the memory store
+	DefaultMemoryShards = 16
+)
+
+// MemoryOption represents a memory store option function.
+type MemoryOption func(o *MemoryOptions)
+
+type MemoryOptions struct {
+	Policy     EvictionPolicy
+	MaxEntries int
+	MaxCost    int64
+	Shards     int
+	OnEvict    EvictionCallback
+
+	DefaultOptions []Option
+}
+
+func applyMemoryOptions(opts ...MemoryOption) *MemoryOptions {
+	o := &MemoryOptions{
+		Shards: DefaultMemoryShards,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.Shards < 1 {
+		o.Shards = 1
+	}
+
+	return o
+}
+
+// WithEvictionPolicy allows to specify the policy used to choose the entries
+// to evict when the store is full. Defaults to EvictionPolicyLRU.
+func WithEvictionPolicy(policy EvictionPolicy) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.Policy = policy
+	}
+}
+
+// WithMaxEntries allows to bound the store by a number of entries.
+// A zero value means no limit.
+func WithMaxEntries(maxEntries int) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.MaxEntries = maxEntries
+	}
+}
+
+// WithMaxCost allows to bound the store by the total cost of its entries, as
+// given by WithCost when setting them. Entries set without a cost count for 1.
+// A zero value means no limit.
+func WithMaxCost(maxCost int64) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.MaxCost = maxCost
+	}
+}
+
+// WithShards allows to specify the number of shards of the store. Keys are
+// spread across shards, each one having its own lock, while the store bounds
+// apply to all of them.
+func WithShards(shards int) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.Shards = shards
+	}
+}
+
+// WithEvictionCallback allows to specify a function called each time an entry
+// is evicted from the store, either because of its expiration or to make room
+// for a new entry.
+func WithEvictionCallback(callback EvictionCallback) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.OnEvict = callback
+	}
+}
+
+// WithDefaultOptions allows to specify the default store options (expiration,
+// tags, ...) used when setting a value.
+func WithDefaultOptions(options ...Option) MemoryOption {
+	return func(o *MemoryOptions) {
+		o.DefaultOptions = options
+	}
+}
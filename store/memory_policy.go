@@ -0,0 +1,115 @@
+package store
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// memoryPolicy keeps track of the entries of a memory store shard in order to
+// choose the next one to evict
+type memoryPolicy interface {
+	add(entry *memoryEntry)
+	touch(entry *memoryEntry)
+	remove(entry *memoryEntry)
+	victim() *memoryEntry
+}
+
+func newMemoryPolicy(policy EvictionPolicy) memoryPolicy {
+	if policy == EvictionPolicyLFU {
+		return &lfuPolicy{}
+	}
+
+	return &lruPolicy{list: list.New()}
+}
+
+// lruPolicy evicts the least recently used entry, using a list ordered by
+// last access
+type lruPolicy struct {
+	list *list.List
+}
+
+func (p *lruPolicy) add(entry *memoryEntry) {
+	entry.element = p.list.PushFront(entry)
+}
+
+func (p *lruPolicy) touch(entry *memoryEntry) {
+	p.list.MoveToFront(entry.element)
+}
+
+func (p *lruPolicy) remove(entry *memoryEntry) {
+	p.list.Remove(entry.element)
+	entry.element = nil
+}
+
+func (p *lruPolicy) victim() *memoryEntry {
+	if back := p.list.Back(); back != nil {
+		return back.Value.(*memoryEntry)
+	}
+	return nil
+}
+
+// lfuPolicy evicts the least frequently used entry, using a min-heap ordered
+// by access count. Entries having the same count are evicted from the least
+// recently used one.
+type lfuPolicy struct {
+	entries lfuHeap
+	tick    uint64
+}
+
+func (p *lfuPolicy) add(entry *memoryEntry) {
+	p.tick++
+	entry.frequency = 1
+	entry.tick = p.tick
+	heap.Push(&p.entries, entry)
+}
+
+func (p *lfuPolicy) touch(entry *memoryEntry) {
+	p.tick++
+	entry.frequency++
+	entry.tick = p.tick
+	heap.Fix(&p.entries, entry.index)
+}
+
+func (p *lfuPolicy) remove(entry *memoryEntry) {
+	heap.Remove(&p.entries, entry.index)
+}
+
+func (p *lfuPolicy) victim() *memoryEntry {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	return p.entries[0]
+}
+
+type lfuHeap []*memoryEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].frequency != h[j].frequency {
+		return h[i].frequency < h[j].frequency
+	}
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	entry := x.(*memoryEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*h = old[:n-1]
+	return entry
+}
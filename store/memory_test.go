@@ -0,0 +1,572 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMemory(t *testing.T) {
+	// When
+	s := store.NewMemory(store.WithDefaultOptions(store.WithExpiration(6 * time.Second)))
+
+	// Then
+	assert.IsType(t, new(store.MemoryStore), s)
+	assert.Equal(t, &store.Options{Expiration: 6 * time.Second}, s.Options)
+}
+
+func TestMemoryGet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	err := s.Set(ctx, "my-key", "my-cache-value")
+	assert.Nil(t, err)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cache-value", value)
+}
+
+func TestMemoryGetWhenNotFound(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestMemoryGetWithTTL(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithDefaultOptions(store.WithExpiration(time.Minute)))
+
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithExpiration(10*time.Second))
+	assert.Nil(t, err)
+
+	err = s.Set(ctx, "my-default-key", "my-cache-value")
+	assert.Nil(t, err)
+
+	// When
+	value, ttl, err := s.GetWithTTL(ctx, "my-key")
+	_, defaultTTL, defaultErr := s.GetWithTTL(ctx, "my-default-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cache-value", value)
+	assert.True(t, ttl > 9*time.Second && ttl <= 10*time.Second)
+
+	assert.Nil(t, defaultErr)
+	assert.True(t, defaultTTL > 59*time.Second && defaultTTL <= time.Minute)
+}
+
+func TestMemoryGetWhenExpired(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := map[string]store.EvictionReason{}
+
+	s := store.NewMemory(store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+		evicted[key] = reason
+	}))
+
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithExpiration(10*time.Millisecond))
+	assert.Nil(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// When
+	value, err := s.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, value)
+	assert.ErrorIs(t, err, store.NotFound{})
+	assert.Equal(t, map[string]store.EvictionReason{"my-key": store.EvictionReasonExpired}, evicted)
+}
+
+func TestMemorySetWhenLRUEviction(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := []string{}
+
+	s := store.NewMemory(
+		store.WithShards(1),
+		store.WithMaxEntries(2),
+		store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+			assert.Equal(t, store.EvictionReasonCapacity, reason)
+			evicted = append(evicted, key)
+		}),
+	)
+
+	_ = s.Set(ctx, "key-1", "value-1")
+	_ = s.Set(ctx, "key-2", "value-2")
+
+	_, err := s.Get(ctx, "key-1")
+	assert.Nil(t, err)
+
+	// When
+	err = s.Set(ctx, "key-3", "value-3")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-2"}, evicted)
+
+	_, err = s.Get(ctx, "key-2")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestMemorySetWhenLFUEviction(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := []string{}
+
+	s := store.NewMemory(
+		store.WithShards(1),
+		store.WithMaxEntries(2),
+		store.WithEvictionPolicy(store.EvictionPolicyLFU),
+		store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+
+	_ = s.Set(ctx, "key-1", "value-1")
+	_ = s.Set(ctx, "key-2", "value-2")
+
+	for i := 0; i < 3; i++ {
+		_, _ = s.Get(ctx, "key-1")
+	}
+	_, _ = s.Get(ctx, "key-2")
+
+	// When
+	err := s.Set(ctx, "key-3", "value-3")
+	assert.Nil(t, err)
+
+	err = s.Set(ctx, "key-4", "value-4")
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, []string{"key-2", "key-3"}, evicted)
+
+	_, err = s.Get(ctx, "key-1")
+	assert.Nil(t, err)
+}
+
+func TestMemorySetWhenMaxCost(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := []string{}
+
+	s := store.NewMemory(
+		store.WithShards(1),
+		store.WithMaxCost(10),
+		store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+
+	_ = s.Set(ctx, "key-1", "value-1", store.WithCost(4))
+	_ = s.Set(ctx, "key-2", "value-2", store.WithCost(4))
+
+	// When
+	err := s.Set(ctx, "key-3", "value-3", store.WithCost(6))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1"}, evicted)
+
+	err = s.Set(ctx, "key-4", "value-4", store.WithCost(11))
+	assert.Equal(t, store.ErrMemoryEntryTooLarge, err)
+}
+
+func TestMemorySetWhenMaxCostAndDefaultShards(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithMaxCost(1000))
+
+	// When
+	err := s.Set(ctx, "key-1", "value-1", store.WithCost(100))
+
+	// Then
+	assert.Nil(t, err)
+
+	value, err := s.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-1", value)
+
+	err = s.Set(ctx, "key-2", "value-2", store.WithCost(1001))
+	assert.Equal(t, store.ErrMemoryEntryTooLarge, err)
+}
+
+func TestMemorySetWhenMaxCostAcrossShards(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithMaxCost(1000))
+
+	// When
+	for i := 0; i < 200; i++ {
+		err := s.Set(ctx, fmt.Sprintf("key-%d", i), "value", store.WithCost(900))
+		assert.Nil(t, err)
+	}
+
+	// Then
+	var cost int64
+	for i := 0; i < 200; i++ {
+		if _, err := s.Get(ctx, fmt.Sprintf("key-%d", i)); err == nil {
+			cost += 900
+		}
+	}
+	assert.LessOrEqual(t, cost, int64(1000))
+
+	_, err := s.Get(ctx, "key-199")
+	assert.Nil(t, err)
+}
+
+func TestMemorySetWhenMaxCostReached(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := []string{}
+
+	s := store.NewMemory(
+		store.WithMaxCost(1000),
+		store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+			evicted = append(evicted, key)
+		}),
+	)
+
+	// When
+	for i := 0; i < 10; i++ {
+		err := s.Set(ctx, fmt.Sprintf("key-%d", i), "value", store.WithCost(100))
+		assert.Nil(t, err)
+	}
+
+	// Then
+	assert.Empty(t, evicted)
+
+	for i := 0; i < 10; i++ {
+		_, err := s.Get(ctx, fmt.Sprintf("key-%d", i))
+		assert.Nil(t, err)
+	}
+
+	err := s.Set(ctx, "key-10", "value", store.WithCost(100))
+	assert.Nil(t, err)
+	assert.Len(t, evicted, 1)
+}
+
+func TestMemorySetWhenExpiredEntries(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	evicted := map[string]store.EvictionReason{}
+
+	s := store.NewMemory(
+		store.WithShards(1),
+		store.WithMaxEntries(2),
+		store.WithEvictionCallback(func(key string, value any, reason store.EvictionReason) {
+			evicted[key] = reason
+		}),
+	)
+
+	_ = s.Set(ctx, "key-1", "value-1")
+	_ = s.Set(ctx, "key-2", "value-2", store.WithExpiration(10*time.Millisecond))
+
+	time.Sleep(20 * time.Millisecond)
+
+	// When
+	err := s.Set(ctx, "key-3", "value-3")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]store.EvictionReason{"key-2": store.EvictionReasonExpired}, evicted)
+
+	value, err := s.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-1", value)
+}
+
+func TestMemoryGetMany(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	})
+	assert.Nil(t, err)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1", "key-2": "value-2"}, values)
+
+	err = s.DeleteMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+
+	values, err = s.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
+
+func TestMemoryDelete(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "my-key", "my-cache-value")
+
+	// When
+	err := s.Delete(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = s.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestMemoryInvalidate(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "key-1", "value-1", store.WithTags([]string{"tag1"}))
+	_ = s.Set(ctx, "key-2", "value-2", store.WithTags([]string{"tag1", "tag2"}))
+	_ = s.Set(ctx, "key-3", "value-3", store.WithTags([]string{"tag2"}))
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = s.Get(ctx, "key-1")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	_, err = s.Get(ctx, "key-2")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	value, err := s.Get(ctx, "key-3")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-3", value)
+}
+
+func TestMemoryInvalidateWhenOverriddenWithoutTags(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+	_ = s.Set(ctx, "my-key", "my-new-cache-value")
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+
+	value, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-new-cache-value", value)
+}
+
+func TestMemoryInvalidateResult(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "key-1", "value-1", store.WithTags([]string{"tag1"}))
+	_ = s.Set(ctx, "key-2", "value-2", store.WithTags([]string{"tag1", "tag2"}))
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2", "tag3"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, store.InvalidateResult{
+		Removed:      2,
+		RemovedByTag: map[string]int{"tag1": 2},
+		TagsNotFound: []string{"tag2", "tag3"},
+	}, *result)
+}
+
+func TestMemoryGetTags(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "key-1", "value-1", store.WithTags([]string{"tag1", "tag2"}))
+	_ = s.Set(ctx, "key-2", "value-2")
+
+	// When
+	tags1, err1 := s.GetTags(ctx, "key-1")
+	tags2, err2 := s.GetTags(ctx, "key-2")
+	_, err3 := s.GetTags(ctx, "key-3")
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Equal(t, []string{"tag1", "tag2"}, tags1)
+
+	assert.Nil(t, err2)
+	assert.Empty(t, tags2)
+
+	assert.ErrorIs(t, err3, store.NotFound{})
+}
+
+func TestMemoryInvalidateByPrefix(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "user:1", "value-1")
+	_ = s.Set(ctx, "user:2", "value-2")
+	_ = s.Set(ctx, "product:1", "value-3", store.WithTags([]string{"tag1"}))
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx,
+		store.WithInvalidatePrefix("user:"),
+		store.WithInvalidateTags([]string{"tag1"}),
+		store.WithInvalidateResult(result),
+	)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Removed)
+
+	for _, key := range []string{"user:1", "user:2", "product:1"} {
+		_, err = s.Get(ctx, key)
+		assert.ErrorIs(t, err, store.NotFound{})
+	}
+}
+
+func TestMemoryInvalidateByPatternAndPredicate(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "user:1:profile", "value-1")
+	_ = s.Set(ctx, "user:2:profile", "value-2")
+	_ = s.Set(ctx, "user:1:settings", "value-3")
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx,
+		store.WithInvalidatePattern("user:*:profile"),
+		store.WithInvalidatePredicate(func(key string) bool {
+			return key != "user:2:profile"
+		}),
+		store.WithInvalidateResult(result),
+	)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Removed)
+
+	_, err = s.Get(ctx, "user:1:profile")
+	assert.ErrorIs(t, err, store.NotFound{})
+
+	value, err := s.Get(ctx, "user:2:profile")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-2", value)
+
+	value, err = s.Get(ctx, "user:1:settings")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-3", value)
+}
+
+func TestMemoryClear(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory()
+
+	_ = s.Set(ctx, "my-key", "my-cache-value")
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = s.Get(ctx, "my-key")
+	assert.ErrorIs(t, err, store.NotFound{})
+}
+
+func TestMemoryGetType(t *testing.T) {
+	// Given
+	s := store.NewMemory()
+
+	// When - Then
+	assert.Equal(t, store.MemoryType, s.GetType())
+}
+
+func TestMemoryConcurrency(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewMemory(store.WithMaxEntries(100), store.WithEvictionPolicy(store.EvictionPolicyLFU))
+
+	// When
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("key-%d", (i*100+j)%300)
+				_ = s.Set(ctx, key, j, store.WithTags([]string{fmt.Sprintf("tag-%d", j%5)}))
+				_, _ = s.Get(ctx, key)
+			}
+			_ = s.Invalidate(ctx, store.WithInvalidateTags([]string{fmt.Sprintf("tag-%d", i%5)}))
+		}(i)
+	}
+	wg.Wait()
+
+	// Then
+	values, err := s.GetMany(ctx, func() []any {
+		keys := []any{}
+		for i := 0; i < 300; i++ {
+			keys = append(keys, fmt.Sprintf("key-%d", i))
+		}
+		return keys
+	}())
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, len(values), 100)
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultNamespaceSeparator is the default separator between a namespace and
+// the keys or tags it prefixes
+const DefaultNamespaceSeparator = ":"
+
+// NamespaceOption represents a namespace store option function.
+type NamespaceOption func(o *NamespaceOptions)
+
+type NamespaceOptions struct {
+	Separator string
+}
+
+func applyNamespaceOptions(opts ...NamespaceOption) *NamespaceOptions {
+	o := &NamespaceOptions{
+		Separator: DefaultNamespaceSeparator,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithNamespaceSeparator allows to specify the separator between the
+// namespace and the keys or tags it prefixes. Defaults to ":".
+func WithNamespaceSeparator(separator string) NamespaceOption {
+	return func(o *NamespaceOptions) {
+		o.Separator = separator
+	}
+}
+
+// NamespaceStore is a store wrapper that prefixes every key and tag with a
+// namespace, so that several applications can share the same storage without
+// colliding. Invalidate and Clear only affect the values of the namespace.
+type NamespaceStore struct {
+	Store     StoreInterface
+	Namespace string
+	Options   *NamespaceOptions
+}
+
+// NewNamespace creates a new store wrapper prefixing the keys and tags of the
+// given store with the given namespace
+func NewNamespace(store StoreInterface, namespace string, options ...NamespaceOption) *NamespaceStore {
+	return &NamespaceStore{
+		Store:     store,
+		Namespace: namespace,
+		Options:   applyNamespaceOptions(options...),
+	}
+}
+
+// Get returns data stored from a given key
+func (s *NamespaceStore) Get(ctx context.Context, key any) (any, error) {
+	return s.Store.Get(ctx, s.key(key))
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL
+func (s *NamespaceStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	return s.Store.GetWithTTL(ctx, s.key(key))
+}
+
+// GetTags returns the tags the value of the given key has been set with,
+// without the namespace, when the wrapped store keeps them
+func (s *NamespaceStore) GetTags(ctx context.Context, key any) ([]string, error) {
+	namespacedTags, err := GetTags(ctx, s.Store, s.key(key))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(namespacedTags))
+	for _, tag := range namespacedTags {
+		tag, ok := strings.CutPrefix(tag, s.prefix())
+		if ok {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// GetMany returns data stored from given keys
+func (s *NamespaceStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	originalKeys := make(map[any]any, len(keys))
+	namespacedKeys := make([]any, 0, len(keys))
+	for _, key := range keys {
+		namespacedKey := s.key(key)
+		if _, ok := originalKeys[namespacedKey]; ok {
+			continue
+		}
+		originalKeys[namespacedKey] = key
+		namespacedKeys = append(namespacedKeys, namespacedKey)
+	}
+
+	values, err := s.Store.GetMany(ctx, namespacedKeys)
+
+	result := make(map[any]any, len(values))
+	for namespacedKey, value := range values {
+		result[originalKeys[namespacedKey]] = value
+	}
+
+	return result, err
+}
+
+// Set defines data in the wrapped store for given key identifier
+func (s *NamespaceStore) Set(ctx context.Context, key any, value any, options ...Option) error {
+	return s.Store.Set(ctx, s.key(key), value, s.options(options)...)
+}
+
+// SetMany defines data in the wrapped store for given key identifiers
+func (s *NamespaceStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	namespacedItems := make(map[any]any, len(items))
+	for key, value := range items {
+		namespacedItems[s.key(key)] = value
+	}
+
+	return s.Store.SetMany(ctx, namespacedItems, s.options(options)...)
+}
+
+// Delete removes data from the wrapped store for given key identifier
+func (s *NamespaceStore) Delete(ctx context.Context, key any) error {
+	return s.Store.Delete(ctx, s.key(key))
+}
+
+// DeleteMany removes data from the wrapped store for given key identifiers
+func (s *NamespaceStore) DeleteMany(ctx context.Context, keys []any) error {
+	namespacedKeys := make([]any, 0, len(keys))
+	for _, key := range keys {
+		namespacedKeys = append(namespacedKeys, s.key(key))
+	}
+
+	return s.Store.DeleteMany(ctx, namespacedKeys)
+}
+
+// Invalidate invalidates the values of the namespace having the given tags,
+// or whose key (without the namespace) matches the given prefix, pattern or
+// predicate
+func (s *NamespaceStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
+	opts := applyInvalidateOptions(options...)
+
+	var result *InvalidateResult
+	if opts.Result != nil {
+		result = &InvalidateResult{}
+	}
+
+	namespacedOptions := []InvalidateOption{
+		WithInvalidateTags(s.tags(opts.Tags)),
+		WithInvalidateResult(result),
+	}
+
+	if opts.matchesKeys() {
+		prefix := s.prefix()
+
+		namespacedOptions = append(namespacedOptions, WithInvalidatePrefix(prefix+opts.Prefix))
+		if opts.Pattern != "" {
+			namespacedOptions = append(namespacedOptions, WithInvalidatePattern(EscapePattern(prefix)+opts.Pattern))
+		}
+		if predicate := opts.Predicate; predicate != nil {
+			namespacedOptions = append(namespacedOptions, WithInvalidatePredicate(func(key string) bool {
+				return predicate(strings.TrimPrefix(key, prefix))
+			}))
+		}
+	}
+
+	err := s.Store.Invalidate(ctx, namespacedOptions...)
+	if result != nil {
+		s.addResult(opts.Result, result)
+	}
+
+	return err
+}
+
+// addResult adds the given result of an invalidation of the wrapped store to
+// the given result, reporting keys and tags without the namespace
+func (s *NamespaceStore) addResult(result *InvalidateResult, namespaced *InvalidateResult) {
+	prefix := s.prefix()
+
+	result.Removed += namespaced.Removed
+	for tag, count := range namespaced.RemovedByTag {
+		if result.RemovedByTag == nil {
+			result.RemovedByTag = map[string]int{}
+		}
+		result.RemovedByTag[strings.TrimPrefix(tag, prefix)] += count
+	}
+	for _, tag := range namespaced.TagsNotFound {
+		result.TagsNotFound = append(result.TagsNotFound, strings.TrimPrefix(tag, prefix))
+	}
+	for _, key := range namespaced.Failed {
+		result.Failed = append(result.Failed, strings.TrimPrefix(key, prefix))
+	}
+	result.Layers = append(result.Layers, namespaced.Layers...)
+}
+
+// Clear removes all the values of the namespace from the wrapped store, by
+// invalidating the keys starting with the namespace. Stores unable to list
+// their keys (Memcache, Ristretto) return an ErrUnsupportedInvalidateOption
+// error.
+func (s *NamespaceStore) Clear(ctx context.Context) error {
+	return s.Store.Invalidate(ctx, WithInvalidatePrefix(s.prefix()))
+}
+
+// GetType returns the type of the wrapped store
+func (s *NamespaceStore) GetType() string {
+	return s.Store.GetType()
+}
+
+func (s *NamespaceStore) prefix() string {
+	return s.Namespace + s.Options.Separator
+}
+
+// key returns the given key prefixed with the namespace
+func (s *NamespaceStore) key(key any) string {
+	return s.prefix() + fmt.Sprint(key)
+}
+
+// tags returns the given tags prefixed with the namespace
+func (s *NamespaceStore) tags(tags []string) []string {
+	namespacedTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		namespacedTags = append(namespacedTags, s.prefix()+tag)
+	}
+	return namespacedTags
+}
+
+// options returns the given options with namespaced tags
+func (s *NamespaceStore) options(options []Option) []Option {
+	opts := applyOptions(options...)
+	if len(opts.Tags) == 0 {
+		return options
+	}
+
+	return append(append([]Option{}, options...), WithTags(s.tags(opts.Tags)))
+}
@@ -0,0 +1,322 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewNamespace(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().GetType().Return("redis")
+
+	// When
+	namespaceStore := store.NewNamespace(s, "my-app", store.WithNamespaceSeparator("/"))
+
+	// Then
+	assert.IsType(t, new(store.NamespaceStore), namespaceStore)
+	assert.Equal(t, s, namespaceStore.Store)
+	assert.Equal(t, "my-app", namespaceStore.Namespace)
+	assert.Equal(t, "/", namespaceStore.Options.Separator)
+	assert.Equal(t, "redis", namespaceStore.GetType())
+}
+
+func TestNamespaceSet(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Set(ctx, "my-app:my-key", "my-cache-value", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ any, _ any, options ...store.Option) error {
+			opts := store.ApplyOptionsWithDefault(&store.Options{}, options...)
+			assert.Equal(t, 10*time.Second, opts.Expiration)
+			assert.Equal(t, []string{"my-app:tag1"}, opts.Tags)
+			return nil
+		})
+
+	namespaceStore := store.NewNamespace(s, "my-app")
+
+	// When
+	err := namespaceStore.Set(ctx, "my-key", "my-cache-value",
+		store.WithExpiration(10*time.Second),
+		store.WithTags([]string{"tag1"}),
+	)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestNamespaceGetWhenSameKeyInAnotherNamespace(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	app1 := store.NewNamespace(memoryStore, "app1")
+	app2 := store.NewNamespace(memoryStore, "app2")
+
+	err := app1.Set(ctx, "my-key", "value-1")
+	assert.Nil(t, err)
+
+	err = app2.Set(ctx, "my-key", "value-2")
+	assert.Nil(t, err)
+
+	// When
+	value1, err1 := app1.Get(ctx, "my-key")
+	value2, err2 := app2.Get(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Equal(t, "value-1", value1)
+
+	assert.Nil(t, err2)
+	assert.Equal(t, "value-2", value2)
+
+	value, err := memoryStore.Get(ctx, "app1:my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-1", value)
+}
+
+func TestNamespaceGetMany(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	s := store.NewNamespace(store.NewMemory(), "my-app")
+
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	})
+	assert.Nil(t, err)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2", "key-3"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	}, values)
+}
+
+func TestNamespaceInvalidate(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	app1 := store.NewNamespace(memoryStore, "app1")
+	app2 := store.NewNamespace(memoryStore, "app2")
+
+	err := app1.Set(ctx, "my-key", "value-1", store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	err = app2.Set(ctx, "my-key", "value-2", store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	// When
+	err = app1.Invalidate(ctx, store.WithInvalidateTags([]string{"book"}))
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = app1.Get(ctx, "my-key")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	value, err := app2.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-2", value)
+}
+
+func TestNamespaceInvalidateByPrefixAndPredicate(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	app1 := store.NewNamespace(memoryStore, "app1")
+	app2 := store.NewNamespace(memoryStore, "app2")
+
+	err := app1.SetMany(ctx, map[any]any{"user:1": "value-1", "user:2": "value-2"})
+	assert.Nil(t, err)
+
+	err = app2.Set(ctx, "user:1", "value-3")
+	assert.Nil(t, err)
+
+	var seen []string
+	result := &store.InvalidateResult{}
+
+	// When
+	err = app1.Invalidate(ctx,
+		store.WithInvalidatePrefix("user:"),
+		store.WithInvalidatePredicate(func(key string) bool {
+			seen = append(seen, key)
+			return key == "user:1"
+		}),
+		store.WithInvalidateResult(result),
+	)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Removed)
+	assert.ElementsMatch(t, []string{"user:1", "user:2"}, seen)
+
+	_, err = app1.Get(ctx, "user:1")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	value, err := app1.Get(ctx, "user:2")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-2", value)
+
+	value, err = app2.Get(ctx, "user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-3", value)
+}
+
+func TestNamespaceInvalidateByPattern(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	app1 := store.NewNamespace(memoryStore, "app[1]")
+
+	err := app1.SetMany(ctx, map[any]any{"user:1:profile": "value-1", "user:1:settings": "value-2"})
+	assert.Nil(t, err)
+
+	// When
+	err = app1.Invalidate(ctx, store.WithInvalidatePattern("user:*:profile"))
+
+	// Then
+	assert.Nil(t, err)
+
+	_, err = app1.Get(ctx, "user:1:profile")
+	assert.True(t, errors.Is(err, store.NotFound{}))
+
+	value, err := app1.Get(ctx, "user:1:settings")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-2", value)
+}
+
+func TestNamespaceInvalidateResult(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	app1 := store.NewNamespace(store.NewMemory(), "app1")
+
+	err := app1.Set(ctx, "my-key", "value-1", store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err = app1.Invalidate(ctx, store.WithInvalidateTags([]string{"book", "movie"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, store.InvalidateResult{
+		Removed:      1,
+		RemovedByTag: map[string]int{"book": 1},
+		TagsNotFound: []string{"movie"},
+	}, *result)
+}
+
+func TestNamespaceGetTags(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	app1 := store.NewNamespace(store.NewMemory(), "app1")
+
+	err := app1.Set(ctx, "my-key", "value-1", store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	// When
+	tags, err := app1.GetTags(ctx, "my-key")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"book"}, tags)
+}
+
+func TestNamespaceGetTagsWhenNotSupported(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	app1 := store.NewNamespace(NewMockStoreInterface(ctrl), "app1")
+
+	// When
+	_, err := app1.GetTags(ctx, "my-key")
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrTagsNotSupported)
+}
+
+func TestNamespaceClear(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	memoryStore := store.NewMemory()
+	app1 := store.NewNamespace(memoryStore, "app1")
+	app2 := store.NewNamespace(memoryStore, "app2")
+
+	err := app1.SetMany(ctx, map[any]any{"key-1": "value-1", "key-2": "value-2"})
+	assert.Nil(t, err)
+
+	err = app2.Set(ctx, "key-1", "value-3")
+	assert.Nil(t, err)
+
+	err = memoryStore.Set(ctx, "raw-key", "raw-value")
+	assert.Nil(t, err)
+
+	// When
+	err = app1.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+
+	values, err := app1.GetMany(ctx, []any{"key-1", "key-2"})
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+
+	value, err := app2.Get(ctx, "key-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value-3", value)
+
+	value, err = memoryStore.Get(ctx, "raw-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "raw-value", value)
+}
+
+func TestNamespaceClearWhenUnsupported(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := store.ErrUnsupportedInvalidateOption
+
+	s := NewMockStoreInterface(ctrl)
+	s.EXPECT().Invalidate(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, options ...store.InvalidateOption) error {
+		opts := store.ApplyInvalidateOptions(options...)
+		assert.Equal(t, "my-app:", opts.Prefix)
+		assert.Empty(t, opts.Tags)
+		return expectedErr
+	})
+
+	namespaceStore := store.NewNamespace(s, "my-app")
+
+	// When
+	err := namespaceStore.Clear(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+}
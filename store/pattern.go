@@ -0,0 +1,109 @@
+package store
+
+import "strings"
+
+// MatchPattern returns whether the given key matches the given glob-style
+// pattern, as matched by Redis: "*" matches any sequence of characters, "?"
+// any character, "[abc]", "[^abc]" and "[a-c]" a character of a set, and "\"
+// escapes the next character.
+func MatchPattern(pattern string, key string) bool {
+	p, k := 0, 0
+	starP, starK := -1, 0
+
+	for k < len(key) {
+		if p < len(pattern) && pattern[p] == '*' {
+			starP, starK = p, k
+			p++
+			continue
+		}
+
+		if p < len(pattern) {
+			if matched, width := matchPatternToken(pattern[p:], key[k]); matched {
+				p += width
+				k++
+				continue
+			}
+		}
+
+		// Backtrack to the last star, making it match one more character
+		if starP < 0 {
+			return false
+		}
+		starK++
+		p, k = starP+1, starK
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+// EscapePattern returns the given string escaped so that it is matched as is
+// by a glob-style pattern
+func EscapePattern(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// matchPatternToken returns whether the given character matches the token
+// (other than "*") starting the given pattern, and the width of the token
+func matchPatternToken(pattern string, c byte) (bool, int) {
+	switch pattern[0] {
+	case '?':
+		return true, 1
+	case '\\':
+		if len(pattern) > 1 {
+			return pattern[1] == c, 2
+		}
+	case '[':
+		return matchPatternClass(pattern, c)
+	}
+
+	return pattern[0] == c, 1
+}
+
+// matchPatternClass returns whether the given character belongs to the set
+// starting the given pattern, and the width of the set
+func matchPatternClass(pattern string, c byte) (bool, int) {
+	i := 1
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+
+	matched := false
+	for i < len(pattern) && pattern[i] != ']' {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			matched = matched || pattern[i+1] == c
+			i += 2
+		case i+2 < len(pattern) && pattern[i+1] == '-' && pattern[i+2] != ']':
+			low, high := pattern[i], pattern[i+2]
+			if low > high {
+				low, high = high, low
+			}
+			matched = matched || (c >= low && c <= high)
+			i += 3
+		default:
+			matched = matched || pattern[i] == c
+			i++
+		}
+	}
+
+	if i < len(pattern) {
+		// Closing bracket
+		i++
+	}
+
+	return matched != negate, i
+}
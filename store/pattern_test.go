@@ -0,0 +1,54 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		key      string
+		expected bool
+	}{
+		{pattern: "*", key: "", expected: true},
+		{pattern: "*", key: "user:1", expected: true},
+		{pattern: "user:*", key: "user:1", expected: true},
+		{pattern: "user:*", key: "users:1", expected: false},
+		{pattern: "*:profile", key: "user:1:profile", expected: true},
+		{pattern: "user:*:profile", key: "user:1:settings", expected: false},
+		{pattern: "user:*:*:profile", key: "user:1:2:profile", expected: true},
+		{pattern: "user:?", key: "user:1", expected: true},
+		{pattern: "user:?", key: "user:12", expected: false},
+		{pattern: "user:[12]", key: "user:2", expected: true},
+		{pattern: "user:[12]", key: "user:3", expected: false},
+		{pattern: "user:[^12]", key: "user:3", expected: true},
+		{pattern: "user:[^12]", key: "user:1", expected: false},
+		{pattern: "user:[a-c]", key: "user:b", expected: true},
+		{pattern: "user:[c-a]", key: "user:b", expected: true},
+		{pattern: "user:[a-c]", key: "user:d", expected: false},
+		{pattern: `user:\*`, key: "user:*", expected: true},
+		{pattern: `user:\*`, key: "user:1", expected: false},
+		{pattern: `user:[\]]`, key: "user:]", expected: true},
+		{pattern: "user", key: "user:1", expected: false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, store.MatchPattern(tc.pattern, tc.key), "%q matching %q", tc.pattern, tc.key)
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	// Given
+	prefix := `user[1]:*?\`
+
+	// When
+	pattern := store.EscapePattern(prefix) + "*"
+
+	// Then
+	assert.Equal(t, `user\[1\]:\*\?\\*`, pattern)
+	assert.True(t, store.MatchPattern(pattern, `user[1]:*?\profile`))
+	assert.False(t, store.MatchPattern(pattern, `user1:*?\profile`))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/XiaoMi/pegasus-go-client/admin"
@@ -37,12 +36,17 @@ type OptionsPegasus struct {
 	TableName         string
 	TablePartitionNum int
 	TableScanNum      int
+
+	// TagTTL and TagIndex configure the tag index, see WithTagTTL and WithTagIndex.
+	TagTTL   time.Duration
+	TagIndex TagIndex
 }
 
 // PegasusStore is a store for Pegasus
 type PegasusStore struct {
-	client  pegasus.Client
-	options *OptionsPegasus
+	client   pegasus.Client
+	options  *OptionsPegasus
+	tagIndex TagIndex
 }
 
 // NewPegasus creates a new store to pegasus instance(s)
@@ -64,10 +68,18 @@ func NewPegasus(ctx context.Context, options *OptionsPegasus) (*PegasusStore, er
 	}
 	defer table.Close()
 
-	return &PegasusStore{
+	if options.Options == nil {
+		options.Options = &Options{}
+	}
+
+	p := &PegasusStore{
 		client:  client,
 		options: options,
-	}, nil
+	}
+	storeOptions := &StoreOptions{TagTTL: options.TagTTL, TagIndex: options.TagIndex}
+	p.tagIndex = storeOptions.tagIndex(pegasusTagStorage{store: p})
+
+	return p, nil
 }
 
 // ValidatePegasusOptions validate pegasus options
@@ -167,6 +179,41 @@ func (p *PegasusStore) GetWithTTL(ctx context.Context, key any) (any, time.Durat
 	return value, time.Duration(ttl) * time.Second, nil
 }
 
+// GetMany returns data stored from given keys using a single BatchGet call
+func (p *PegasusStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	table, err := p.client.OpenTable(ctx, p.options.TableName)
+	if err != nil {
+		return values, err
+	}
+	defer table.Close()
+
+	compositeKeys := make([]pegasus.CompositeKey, 0, len(keys))
+	for _, key := range keys {
+		compositeKeys = append(compositeKeys, pegasus.CompositeKey{
+			HashKey: []byte(cast.ToString(key)),
+			SortKey: empty,
+		})
+	}
+
+	results, err := table.BatchGet(ctx, compositeKeys)
+	if err != nil {
+		return values, err
+	}
+
+	for i, value := range results {
+		if value != nil && i < len(keys) {
+			values[keys[i]] = value
+		}
+	}
+
+	return values, nil
+}
+
 // Set defines data in Pegasus for given key identifier
 func (p *PegasusStore) Set(ctx context.Context, key, value any, options ...Option) error {
 	opts := applyOptions(options...)
@@ -183,80 +230,108 @@ func (p *PegasusStore) Set(ctx context.Context, key, value any, options ...Optio
 	}
 
 	if tags := opts.Tags; len(tags) > 0 {
-		if err = p.SetTags(ctx, key, tags); err != nil {
-			return err
-		}
+		return p.tagIndex.Add(ctx, cast.ToString(key), opts.Expiration, tags)
 	}
 	return nil
 }
 
+// SetMany defines data in Pegasus for given key identifiers
+func (p *PegasusStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, p, items, options...)
+}
+
+// SetTags associates the given key with the given tags
 func (p *PegasusStore) SetTags(ctx context.Context, key any, tags []string) error {
-	for _, tag := range tags {
-		tagKey := fmt.Sprintf(PegasusTagPattern, tag)
-		cacheKeys := []string{}
+	return p.tagIndex.Add(ctx, cast.ToString(key), 0, tags)
+}
 
-		if result, err := p.Get(ctx, tagKey); err == nil {
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
-			}
-		}
+// Delete removes data from Pegasus for given key identifier
+func (p *PegasusStore) Delete(ctx context.Context, key any) error {
+	table, err := p.client.OpenTable(ctx, p.options.TableName)
+	if err != nil {
+		return err
+	}
+	defer table.Close()
 
-		alreadyInserted := false
-		for _, cacheKey := range cacheKeys {
-			if cacheKey == key.(string) {
-				alreadyInserted = true
-				break
-			}
-		}
+	return table.Del(ctx, []byte(cast.ToString(key)), empty)
+}
 
-		if !alreadyInserted {
-			cacheKeys = append(cacheKeys, key.(string))
+// DeleteMany removes data from Pegasus for given key identifiers
+func (p *PegasusStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, p, keys)
+}
+
+// Invalidate invalidates some cache data in Pegasus for given options. Keys
+// matching a prefix, pattern or predicate are found using a full scan of the
+// table.
+func (p *PegasusStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
+	opts := applyInvalidateOptions(options...)
+	errs := []error{}
+
+	for _, tag := range opts.Tags {
+		cacheKeys, err := p.tagIndex.Pop(ctx, tag)
+		if err == nil {
+			err = opts.deleteTagKeys(tag, cacheKeys, func(key string) (bool, error) {
+				return true, p.Delete(ctx, key)
+			})
 		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate tag %q: %w", tag, err))
+		}
+	}
 
-		if err := p.Set(ctx, tagKey, []byte(strings.Join(cacheKeys, ",")), WithExpiration(720*time.Hour)); err != nil {
-			return err
+	if opts.matchesKeys() {
+		if err := p.invalidateKeys(ctx, opts); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// Delete removes data from Pegasus for given key identifier
-func (p *PegasusStore) Delete(ctx context.Context, key any) error {
+// invalidateKeys deletes the keys matching the prefix, pattern or predicate
+// of the given options, using a full scan of the table
+func (p *PegasusStore) invalidateKeys(ctx context.Context, opts *InvalidateOptions) error {
 	table, err := p.client.OpenTable(ctx, p.options.TableName)
 	if err != nil {
 		return err
 	}
 	defer table.Close()
 
-	return table.Del(ctx, []byte(cast.ToString(key)), empty)
-}
+	scanners, err := table.GetUnorderedScanners(ctx, p.options.TablePartitionNum, &pegasus.ScannerOptions{
+		BatchSize: p.options.TableScanNum,
+		NoValue:   true,
+	})
+	if err != nil {
+		return err
+	}
 
-// Invalidate invalidates some cache data in Pegasus for given options
-func (p *PegasusStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
-	opts := applyInvalidateOptions(options...)
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(PegasusTagPattern, tag)
-			result, err := p.Get(ctx, tagKey)
+	errs := []error{}
+
+	for _, scanner := range scanners {
+		keys := []string{}
+		for {
+			completed, hashKey, _, _, err := scanner.Next(ctx)
 			if err != nil {
-				return nil
+				return errors.Join(append(errs, err)...)
 			}
-
-			cacheKeys := []string{}
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
+			if completed {
+				break
 			}
-
-			for _, cacheKey := range cacheKeys {
-				if err := p.Delete(ctx, cacheKey); err != nil {
-					return err
-				}
+			if opts.matchKey(string(hashKey)) {
+				keys = append(keys, string(hashKey))
 			}
 		}
+
+		err := opts.deleteKeys(keys, func(key string) (bool, error) {
+			return true, table.Del(ctx, []byte(key), empty)
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // Clear resets all data in the store
@@ -301,3 +376,59 @@ func (p *PegasusStore) Clear(ctx context.Context) error {
 func (p *PegasusStore) GetType() string {
 	return PegasusType
 }
+
+// pegasusTagStorage stores the tag index of a PegasusStore in Pegasus,
+// detecting concurrent modifications using CheckAndSet
+type pegasusTagStorage struct {
+	store *PegasusStore
+}
+
+func (t pegasusTagStorage) LoadTag(ctx context.Context, tag string) ([]byte, any, error) {
+	table, err := t.store.client.OpenTable(ctx, t.store.options.TableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer table.Close()
+
+	value, err := table.Get(ctx, []byte(fmt.Sprintf(PegasusTagPattern, tag)), empty)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return value, value, nil
+}
+
+func (t pegasusTagStorage) StoreTag(ctx context.Context, tag string, members []byte, token any, expiration time.Duration) error {
+	table, err := t.store.client.OpenTable(ctx, t.store.options.TableName)
+	if err != nil {
+		return err
+	}
+	defer table.Close()
+
+	checkType, operand := pegasus.CheckTypeValueNotExist, []byte(nil)
+	if previous, ok := token.([]byte); ok && previous != nil {
+		checkType, operand = pegasus.CheckTypeBytesEqual, previous
+	}
+
+	result, err := table.CheckAndSet(ctx, []byte(fmt.Sprintf(PegasusTagPattern, tag)), empty, checkType, operand, empty, members, &pegasus.CheckAndSetOptions{
+		SetValueTTLSeconds: int(expiration.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+	if !result.SetSucceed {
+		return ErrTagIndexConflict
+	}
+
+	return nil
+}
+
+func (t pegasusTagStorage) DeleteTag(ctx context.Context, tag string) error {
+	table, err := t.store.client.OpenTable(ctx, t.store.options.TableName)
+	if err != nil {
+		return err
+	}
+	defer table.Close()
+
+	return table.Del(ctx, []byte(fmt.Sprintf(PegasusTagPattern, tag)), empty)
+}
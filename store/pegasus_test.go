@@ -213,6 +213,32 @@ func TestPegasusStore_Invalidate(t *testing.T) {
 	})
 }
 
+func TestPegasusStore_InvalidateByPrefix(t *testing.T) {
+	Convey("Pegasus TestInvalidateByPrefix for pegasus store", t, func() {
+		skipPegasusTest(t)
+
+		ctx := context.Background()
+
+		p, _ := store.NewPegasus(ctx, testPegasusOptions())
+		defer func() {
+			_ = p.Close()
+		}()
+
+		_ = p.Set(ctx, "test-gocache-user:1", "test-gocache-value")
+		_ = p.Set(ctx, "test-gocache-user:2", "test-gocache-value")
+		_ = p.Set(ctx, "test-gocache-product:1", "test-gocache-value")
+
+		result := &store.InvalidateResult{}
+		err := p.Invalidate(ctx, store.WithInvalidatePrefix("test-gocache-user:"), store.WithInvalidateResult(result))
+		So(err, ShouldBeNil)
+		So(result.Removed, ShouldEqual, 2)
+
+		value, err := p.Get(ctx, "test-gocache-product:1")
+		So(err, ShouldBeNil)
+		So(cast.ToString(value), ShouldEqual, "test-gocache-value")
+	})
+}
+
 func TestPegasusStore_Clear(t *testing.T) {
 	Convey("Pegasus TestClear for pegasus store", t, func() {
 		skipPegasusTest(t)
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 // RedisClientInterface represents a go-redis/redis client
 type RedisClientInterface interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
+	MGet(ctx context.Context, keys ...string) *redis.SliceCmd
 	TTL(ctx context.Context, key string) *redis.DurationCmd
 	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
 	Set(ctx context.Context, key string, values any, expiration time.Duration) *redis.StatusCmd
@@ -20,6 +22,10 @@ type RedisClientInterface interface {
 	FlushAll(ctx context.Context) *redis.StatusCmd
 	SAdd(ctx context.Context, key string, members ...any) *redis.IntCmd
 	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+	Unlink(ctx context.Context, keys ...string) *redis.IntCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
 }
 
 const (
@@ -27,19 +33,33 @@ const (
 	RedisType = "redis"
 	// RedisTagPattern represents the tag pattern to be used as a key in specified storage
 	RedisTagPattern = "gocache_tag_%s"
+	// RedisClearBatchSize is the number of keys removed at once by Clear
+	RedisClearBatchSize = 1000
+	// RedisUnlinkBatchSize is the number of keys unlinked at once when
+	// invalidating tags
+	RedisUnlinkBatchSize = 1000
 )
 
+// ErrClearPrefixRequired is returned by Clear on Redis stores created without
+// a clear prefix, unless they flush the whole server
+var ErrClearPrefixRequired = errors.New("clear requires a key prefix (see WithClearPrefix) or WithFlushOnClear")
+
 // RedisStore is a store for Redis
 type RedisStore struct {
 	Client  RedisClientInterface
 	Options *Options
+
+	storeOptions *StoreOptions
 }
 
 // NewRedis creates a new store to Redis instance(s)
-func NewRedis(client RedisClientInterface, options ...Option) *RedisStore {
+func NewRedis(client RedisClientInterface, options ...StoreOption) *RedisStore {
+	storeOptions := applyStoreOptions(options...)
+
 	return &RedisStore{
-		Client:  client,
-		Options: applyOptions(options...),
+		Client:       client,
+		Options:      applyOptions(storeOptions.DefaultOptions...),
+		storeOptions: storeOptions,
 	}
 }
 
@@ -70,28 +90,158 @@ func (s *RedisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duratio
 	return object, ttl, err
 }
 
-// Set defines data in Redis for given key identifier
+// GetMany returns data stored from given keys using a single MGET command
+func (s *RedisStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		redisKeys = append(redisKeys, key.(string))
+	}
+
+	objects, err := s.Client.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		return values, err
+	}
+
+	for i, object := range objects {
+		if object != nil && i < len(keys) {
+			values[keys[i]] = object
+		}
+	}
+
+	return values, nil
+}
+
+// Set defines data in Redis for given key identifier. The value is written
+// along with its tags in a single transaction.
 func (s *RedisStore) Set(ctx context.Context, key any, value any, options ...Option) error {
 	opts := ApplyOptionsWithDefault(s.Options, options...)
 
-	err := s.Client.Set(ctx, key.(string), value, opts.Expiration).Err()
-	if err != nil {
-		return err
+	if len(opts.Tags) == 0 {
+		return s.Client.Set(ctx, key.(string), value, opts.Expiration).Err()
 	}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		s.setTags(ctx, key, tags)
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key.(string), value, opts.Expiration)
+		setTags(ctx, pipe, []string{key.(string)}, opts.Tags, s.storeOptions.tagTTL())
+		return nil
+	})
+	return err
+}
+
+// SetMany defines data in Redis for given key identifiers, along with their
+// tags, in a single transaction
+func (s *RedisStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	if len(items) == 0 {
+		return nil
 	}
 
-	return nil
+	opts := ApplyOptionsWithDefault(s.Options, options...)
+
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		keys := make([]string, 0, len(items))
+		for key, value := range items {
+			pipe.Set(ctx, key.(string), value, opts.Expiration)
+			keys = append(keys, key.(string))
+		}
+		setTags(ctx, pipe, keys, opts.Tags, s.storeOptions.tagTTL())
+		return nil
+	})
+	return err
 }
 
-func (s *RedisStore) setTags(ctx context.Context, key any, tags []string) {
+// setTags adds the given keys to the sets of the given tags using the given
+// pipeline, the sets expiring after the given duration
+func setTags(ctx context.Context, pipe redis.Pipeliner, keys []string, tags []string, ttl time.Duration) {
+	members := make([]any, 0, len(keys))
+	for _, key := range keys {
+		members = append(members, key)
+	}
+
 	for _, tag := range tags {
 		tagKey := fmt.Sprintf(RedisTagPattern, tag)
-		s.Client.SAdd(ctx, tagKey, key.(string))
-		s.Client.Expire(ctx, tagKey, 720*time.Hour)
+		pipe.SAdd(ctx, tagKey, members...)
+		pipe.Expire(ctx, tagKey, ttl)
+	}
+}
+
+// popTags returns the keys of each of the given tags and removes the tags. Each tag is
+// read and removed atomically, so that keys tagged meanwhile are kept in a new
+// tag.
+func popTags(
+	ctx context.Context,
+	txPipelined func(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error),
+	tags []string,
+) ([][]string, error) {
+	cmds := make([]*redis.StringSliceCmd, 0, len(tags))
+
+	_, err := txPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			tagKey := fmt.Sprintf(RedisTagPattern, tag)
+			cmds = append(cmds, pipe.SMembers(ctx, tagKey))
+			pipe.Unlink(ctx, tagKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([][]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		keys = append(keys, cmd.Val())
+	}
+
+	return keys, nil
+}
+
+// unlinkBatch is a batch of keys unlinked using a single UNLINK command, for
+// the given tag (empty for keys matched by prefix, pattern or predicate)
+type unlinkBatch struct {
+	tag  string
+	keys []string
+}
+
+// unlinkBatches unlinks the given batches of keys using a pipeline, recording
+// in the result the keys removed for each tag and the keys failing to be
+// removed
+func unlinkBatches(
+	ctx context.Context,
+	pipelined func(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error),
+	opts *InvalidateOptions,
+	batches []unlinkBatch,
+) error {
+	if len(batches) == 0 {
+		return nil
+	}
+
+	cmds := make([]*redis.IntCmd, 0, len(batches))
+
+	_, err := pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, batch := range batches {
+			cmds = append(cmds, pipe.Unlink(ctx, batch.keys...))
+		}
+		return nil
+	})
+
+	for i, batch := range batches {
+		if i >= len(cmds) || cmds[i].Err() != nil {
+			opts.failed(batch.keys...)
+			continue
+		}
+
+		if batch.tag == "" {
+			opts.removed(int(cmds[i].Val()))
+		} else {
+			opts.tagRemoved(batch.tag, int(cmds[i].Val()))
+		}
 	}
+
+	return err
 }
 
 // Delete removes data from Redis for given key identifier
@@ -100,27 +250,104 @@ func (s *RedisStore) Delete(ctx context.Context, key any) error {
 	return err
 }
 
-// Invalidate invalidates some cache data in Redis for given options
+// DeleteMany removes data from Redis for given key identifiers using a single
+// DEL command
+func (s *RedisStore) DeleteMany(ctx context.Context, keys []any) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		redisKeys = append(redisKeys, key.(string))
+	}
+
+	_, err := s.Client.Del(ctx, redisKeys...).Result()
+	return err
+}
+
+// Invalidate invalidates some cache data in Redis for given options. Tagged
+// keys are unlinked by batches using a pipeline, and keys matching a prefix,
+// pattern or predicate are scanned using SCAN MATCH and unlinked by batches.
 func (s *RedisStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
+	errs := []error{}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(RedisTagPattern, tag)
-			cacheKeys, err := s.Client.SMembers(ctx, tagKey).Result()
-			if err != nil {
-				continue
+	if len(opts.Tags) > 0 {
+		if err := s.invalidateTags(ctx, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.matchesKeys() {
+		if err := s.invalidateKeys(ctx, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *RedisStore) invalidateTags(ctx context.Context, opts *InvalidateOptions) error {
+	tagsKeys, err := popTags(ctx, s.Client.TxPipelined, opts.Tags)
+	if err != nil {
+		return err
+	}
+
+	batches := []unlinkBatch{}
+	for i, tag := range opts.Tags {
+		keys := tagsKeys[i]
+		if len(keys) == 0 {
+			opts.tagNotFound(tag)
+			continue
+		}
+
+		for start := 0; start < len(keys); start += RedisUnlinkBatchSize {
+			end := start + RedisUnlinkBatchSize
+			if end > len(keys) {
+				end = len(keys)
 			}
+			batches = append(batches, unlinkBatch{tag: tag, keys: keys[start:end]})
+		}
+	}
+
+	return unlinkBatches(ctx, s.Client.Pipelined, opts, batches)
+}
+
+// invalidateKeys unlinks the keys matching the prefix, pattern or predicate of
+// the given options, scanned by batches
+func (s *RedisStore) invalidateKeys(ctx context.Context, opts *InvalidateOptions) error {
+	errs := []error{}
+
+	var cursor uint64
+	for {
+		keys, nextCursor, err := s.Client.Scan(ctx, cursor, opts.scanPattern(), RedisClearBatchSize).Result()
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
 
-			for _, cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
+		matchingKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if opts.matchKey(key) {
+				matchingKeys = append(matchingKeys, key)
 			}
+		}
+
+		if len(matchingKeys) > 0 {
+			unlinked, err := s.Client.Unlink(ctx, matchingKeys...).Result()
+			if err != nil {
+				opts.failed(matchingKeys...)
+				errs = append(errs, err)
+			} else {
+				opts.removed(int(unlinked))
+			}
+		}
 
-			_ = s.Delete(ctx, tagKey)
+		cursor = nextCursor
+		if cursor == 0 {
+			return errors.Join(errs...)
 		}
 	}
-
-	return nil
 }
 
 // GetType returns the store type
@@ -128,11 +355,22 @@ func (s *RedisStore) GetType() string {
 	return RedisType
 }
 
-// Clear resets all data in the store
+// Clear removes the keys starting with the clear prefix, scanned using SCAN
+// MATCH and unlinked by batches. When the store is created using
+// WithFlushOnClear, the whole Redis server is flushed instead. Tag sets are
+// kept, and expire along with their time to live.
 func (s *RedisStore) Clear(ctx context.Context) error {
-	if err := s.Client.FlushAll(ctx).Err(); err != nil {
-		return err
+	if s.storeOptions.FlushOnClear {
+		if err := s.Client.FlushAll(ctx).Err(); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	if s.storeOptions.ClearPrefix == "" {
+		return ErrClearPrefixRequired
 	}
 
-	return nil
+	return s.invalidateKeys(ctx, &InvalidateOptions{Prefix: s.storeOptions.ClearPrefix})
 }
@@ -2,6 +2,7 @@ package store_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -26,6 +27,19 @@ func TestNewRedis(t *testing.T) {
 	assert.Equal(t, &store.Options{Expiration: 6 * time.Second}, s.Options)
 }
 
+func TestNewRedisWhenStoreSettings(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	client := NewMockRedisClientInterface(ctrl)
+
+	// When
+	s := store.NewRedis(client, store.WithClearPrefix("my-app:"), store.WithExpiration(6*time.Second), store.WithTagTTL(time.Hour))
+
+	// Then
+	assert.Equal(t, &store.Options{Expiration: 6 * time.Second}, s.Options)
+}
+
 func TestRedisGet(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -96,10 +110,10 @@ func TestRedisSetWithTags(t *testing.T) {
 	cacheKey := "my-key"
 	cacheValue := "my-cache-value"
 
+	pipe := newFakePipeliner(nil)
+
 	client := NewMockRedisClientInterface(ctrl)
-	client.EXPECT().Set(ctx, cacheKey, cacheValue, time.Duration(0)).Return(&redis.StatusCmd{})
-	client.EXPECT().SAdd(ctx, "gocache_tag_tag1", "my-key").Return(&redis.IntCmd{})
-	client.EXPECT().Expire(ctx, "gocache_tag_tag1", 720*time.Hour).Return(&redis.BoolCmd{})
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
 
 	s := store.NewRedis(client)
 
@@ -108,6 +122,50 @@ func TestRedisSetWithTags(t *testing.T) {
 
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"my-key": cacheValue}, pipe.sets)
+	assert.Equal(t, map[string][]any{"gocache_tag_tag1": {"my-key"}}, pipe.sadds)
+	assert.Equal(t, map[string]time.Duration{"gocache_tag_tag1": 720 * time.Hour}, pipe.expires)
+}
+
+func TestRedisSetWithTagsWhenTagTTL(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(nil)
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
+
+	s := store.NewRedis(client, store.WithTagTTL(time.Hour))
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"gocache_tag_tag1": time.Hour}, pipe.expires)
+}
+
+func TestRedisSetWithTagsWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("EXECABORT Transaction discarded because of previous errors")
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).Return(nil, expectedErr)
+
+	s := store.NewRedis(client)
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Equal(t, expectedErr, err)
 }
 
 func TestRedisDelete(t *testing.T) {
@@ -136,19 +194,174 @@ func TestRedisInvalidate(t *testing.T) {
 
 	ctx := context.Background()
 
-	cacheKeys := &redis.StringSliceCmd{}
+	tx := newFakePipeliner(nil)
+	tx.members["gocache_tag_tag1"] = []string{"key-1", "key-2"}
+	tx.members["gocache_tag_tag2"] = []string{"key-3"}
+
+	pipe := newFakePipeliner(nil)
 
 	client := NewMockRedisClientInterface(ctrl)
-	client.EXPECT().SMembers(ctx, "gocache_tag_tag1").Return(cacheKeys)
-	client.EXPECT().Del(ctx, "gocache_tag_tag1").Return(&redis.IntCmd{})
+	gomock.InOrder(
+		client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(tx.run),
+		client.EXPECT().Pipelined(ctx, gomock.Any()).DoAndReturn(pipe.run),
+	)
+
+	s := store.NewRedis(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2", "tag3"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"gocache_tag_tag1"}, {"gocache_tag_tag2"}, {"gocache_tag_tag3"}}, tx.unlinks)
+	assert.Equal(t, [][]string{{"key-1", "key-2"}, {"key-3"}}, pipe.unlinks)
+
+	assert.Equal(t, store.InvalidateResult{
+		Removed:      3,
+		RemovedByTag: map[string]int{"tag1": 2, "tag2": 1},
+		TagsNotFound: []string{"tag3"},
+	}, *result)
+}
+
+func TestRedisInvalidateWhenUnlinkError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	tx := newFakePipeliner(nil)
+	tx.members["gocache_tag_tag1"] = []string{"key-1", "key-2"}
+
+	expectedErr := errors.New("unable to unlink keys")
+
+	client := NewMockRedisClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(tx.run),
+		client.EXPECT().Pipelined(ctx, gomock.Any()).Return(nil, expectedErr),
+	)
+
+	s := store.NewRedis(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, []string{"key-1", "key-2"}, result.Failed)
+}
+
+func TestRedisInvalidateWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to read tags")
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).Return(nil, expectedErr)
 
 	s := store.NewRedis(client)
 
 	// When
 	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
 
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestRedisInvalidateByPrefix(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	firstPage := redis.NewScanCmdResult([]string{"user[1]:a", "user[1]:b"}, 12, nil)
+	lastPage := redis.NewScanCmdResult([]string{"user[1]:c"}, 0, nil)
+
+	client := NewMockRedisClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Scan(ctx, uint64(0), `user\[1\]:*`, int64(store.RedisClearBatchSize)).Return(firstPage),
+		client.EXPECT().Unlink(ctx, "user[1]:a", "user[1]:b").Return(redis.NewIntResult(2, nil)),
+		client.EXPECT().Scan(ctx, uint64(12), `user\[1\]:*`, int64(store.RedisClearBatchSize)).Return(lastPage),
+		client.EXPECT().Unlink(ctx, "user[1]:c").Return(redis.NewIntResult(0, nil)),
+	)
+
+	s := store.NewRedis(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePrefix("user[1]:"), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Removed)
+}
+
+func TestRedisInvalidateByPatternAndPredicate(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	tx := newFakePipeliner(nil)
+	tx.members["gocache_tag_tag1"] = []string{"key-1", "key-2"}
+
+	pipe := newFakePipeliner(nil)
+
+	page := redis.NewScanCmdResult([]string{"user:1:profile", "user:2:profile"}, 0, nil)
+
+	client := NewMockRedisClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(tx.run),
+		client.EXPECT().Pipelined(ctx, gomock.Any()).DoAndReturn(pipe.run),
+		client.EXPECT().Scan(ctx, uint64(0), "user:*:profile", int64(store.RedisClearBatchSize)).Return(page),
+		client.EXPECT().Unlink(ctx, "user:1:profile").Return(redis.NewIntResult(1, nil)),
+	)
+
+	s := store.NewRedis(client)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err := s.Invalidate(ctx,
+		store.WithInvalidateTags([]string{"tag1"}),
+		store.WithInvalidatePattern("user:*:profile"),
+		store.WithInvalidatePredicate(func(key string) bool {
+			return key != "user:2:profile"
+		}),
+		store.WithInvalidateResult(result),
+	)
+
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Removed)
+}
+
+func TestRedisInvalidateByPrefixWhenScanError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to scan keys")
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().Scan(ctx, uint64(0), "user:*", int64(store.RedisClearBatchSize)).
+		Return(redis.NewScanCmdResult(nil, 0, expectedErr))
+
+	s := store.NewRedis(client)
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePrefix("user:"))
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
 }
 
 func TestRedisClear(t *testing.T) {
@@ -160,7 +373,7 @@ func TestRedisClear(t *testing.T) {
 	client := NewMockRedisClientInterface(ctrl)
 	client.EXPECT().FlushAll(ctx).Return(&redis.StatusCmd{})
 
-	s := store.NewRedis(client)
+	s := store.NewRedis(client, store.WithFlushOnClear())
 
 	// When
 	err := s.Clear(ctx)
@@ -169,6 +382,71 @@ func TestRedisClear(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRedisClearWhenClearPrefix(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	firstPage := redis.NewScanCmdResult([]string{"my-app:1", "my-app:2"}, 12, nil)
+	lastPage := redis.NewScanCmdResult([]string{"my-app:3"}, 0, nil)
+
+	client := NewMockRedisClientInterface(ctrl)
+	gomock.InOrder(
+		client.EXPECT().Scan(ctx, uint64(0), "my-app:*", int64(store.RedisClearBatchSize)).Return(firstPage),
+		client.EXPECT().Unlink(ctx, "my-app:1", "my-app:2").Return(redis.NewIntResult(2, nil)),
+		client.EXPECT().Scan(ctx, uint64(12), "my-app:*", int64(store.RedisClearBatchSize)).Return(lastPage),
+		client.EXPECT().Unlink(ctx, "my-app:3").Return(redis.NewIntResult(1, nil)),
+	)
+
+	s := store.NewRedis(client, store.WithClearPrefix("my-app:"))
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestRedisClearWhenScanError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to scan keys")
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().Scan(ctx, uint64(0), "my-app:*", int64(store.RedisClearBatchSize)).
+		Return(redis.NewScanCmdResult(nil, 0, expectedErr))
+
+	s := store.NewRedis(client, store.WithClearPrefix("my-app:"))
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
+}
+
+func TestRedisClearWhenNoClearPrefix(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	// Nothing is removed
+	client := NewMockRedisClientInterface(ctrl)
+
+	s := store.NewRedis(client)
+
+	// When
+	err := s.Clear(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrClearPrefixRequired)
+}
+
 func TestRedisGetType(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -180,3 +458,137 @@ func TestRedisGetType(t *testing.T) {
 	// When - Then
 	assert.Equal(t, store.RedisType, s.GetType())
 }
+
+// fakePipeliner records the commands queued in a pipeline and returns the
+// results of the given values
+type fakePipeliner struct {
+	redis.Pipeliner
+
+	values  map[string]string
+	members map[string][]string
+	sets    map[string]any
+	sadds   map[string][]any
+	expires map[string]time.Duration
+	dels    []string
+	unlinks [][]string
+	cmds    []redis.Cmder
+}
+
+func newFakePipeliner(values map[string]string) *fakePipeliner {
+	return &fakePipeliner{
+		values:  values,
+		members: map[string][]string{},
+		sets:    map[string]any{},
+		sadds:   map[string][]any{},
+		expires: map[string]time.Duration{},
+	}
+}
+
+func (p *fakePipeliner) Get(_ context.Context, key string) *redis.StringCmd {
+	if value, ok := p.values[key]; ok {
+		return redis.NewStringResult(value, nil)
+	}
+	return redis.NewStringResult("", redis.Nil)
+}
+
+func (p *fakePipeliner) Set(_ context.Context, key string, value any, _ time.Duration) *redis.StatusCmd {
+	p.sets[key] = value
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (p *fakePipeliner) Del(_ context.Context, keys ...string) *redis.IntCmd {
+	p.dels = append(p.dels, keys...)
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+func (p *fakePipeliner) SAdd(_ context.Context, key string, members ...any) *redis.IntCmd {
+	p.sadds[key] = append(p.sadds[key], members...)
+	return redis.NewIntResult(int64(len(members)), nil)
+}
+
+func (p *fakePipeliner) Expire(_ context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	p.expires[key] = expiration
+	return redis.NewBoolResult(true, nil)
+}
+
+func (p *fakePipeliner) SMembers(_ context.Context, key string) *redis.StringSliceCmd {
+	return redis.NewStringSliceResult(p.members[key], nil)
+}
+
+func (p *fakePipeliner) Unlink(ctx context.Context, keys ...string) *redis.IntCmd {
+	p.unlinks = append(p.unlinks, keys)
+
+	cmd := redis.NewIntCmd(ctx, "unlink")
+	cmd.SetVal(int64(len(keys)))
+	p.cmds = append(p.cmds, cmd)
+
+	return cmd
+}
+
+func (p *fakePipeliner) run(_ context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	p.cmds = nil
+	err := fn(p)
+	return p.cmds, err
+}
+
+func TestRedisGetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().MGet(ctx, "key-1", "key-2").Return(redis.NewSliceResult([]any{"value-1", nil}, nil))
+
+	s := store.NewRedis(client)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
+}
+
+func TestRedisSetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(nil)
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
+
+	s := store.NewRedis(client)
+
+	// When
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	}, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"key-1": "value-1", "key-2": "value-2"}, pipe.sets)
+	assert.ElementsMatch(t, []any{"key-1", "key-2"}, pipe.sadds["gocache_tag_tag1"])
+}
+
+func TestRedisDeleteMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockRedisClientInterface(ctrl)
+	client.EXPECT().Del(ctx, "key-1", "key-2").Return(&redis.IntCmd{})
+
+	s := store.NewRedis(client)
+
+	// When
+	err := s.DeleteMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+}
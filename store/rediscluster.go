@@ -2,7 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -20,6 +23,9 @@ type RedisClusterClientInterface interface {
 	FlushAll(ctx context.Context) *redis.StatusCmd
 	SAdd(ctx context.Context, key string, members ...any) *redis.IntCmd
 	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+	ForEachMaster(ctx context.Context, fn func(ctx context.Context, client *redis.Client) error) error
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
 }
 
 const (
@@ -27,19 +33,32 @@ const (
 	RedisClusterType = "rediscluster"
 	// RedisClusterTagPattern represents the tag pattern to be used as a key in specified storage
 	RedisClusterTagPattern = "gocache_tag_%s"
+	// RedisClusterTagIndexPattern represents the pattern of the key indexing
+	// the keys of a tag belonging to a hash slot, when tags are indexed per
+	// slot. It starts with a hash tag of the slot.
+	RedisClusterTagIndexPattern = "gocache_tag_{%s}_%s"
+	// RedisClusterTagSlotsPattern represents the pattern of the key
+	// referencing the hash slots of the keys of a tag, when tags are indexed
+	// per slot
+	RedisClusterTagSlotsPattern = "gocache_tag_slots_%s"
 )
 
 // RedisClusterStore is a store for Redis
 type RedisClusterStore struct {
 	Clusclient RedisClusterClientInterface
 	Options    *Options
+
+	storeOptions *StoreOptions
 }
 
 // NewRedisCluster creates a new store to Redis instance(s)
-func NewRedisCluster(client RedisClusterClientInterface, options ...Option) *RedisClusterStore {
+func NewRedisCluster(client RedisClusterClientInterface, options ...StoreOption) *RedisClusterStore {
+	storeOptions := applyStoreOptions(options...)
+
 	return &RedisClusterStore{
-		Clusclient: client,
-		Options:    applyOptions(options...),
+		Clusclient:   client,
+		Options:      applyOptions(storeOptions.DefaultOptions...),
+		storeOptions: storeOptions,
 	}
 }
 
@@ -70,66 +89,349 @@ func (s *RedisClusterStore) GetWithTTL(ctx context.Context, key any) (any, time.
 	return object, ttl, err
 }
 
-// Set defines data in Redis for given key identifier
+// GetMany returns data stored from given keys. Keys are retrieved using a
+// pipeline of GET commands so that they can belong to different hash slots.
+func (s *RedisClusterStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	values := make(map[any]any, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	cmds := make([]*redis.StringCmd, 0, len(keys))
+
+	_, err := s.Clusclient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			cmds = append(cmds, pipe.Get(ctx, key.(string)))
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return values, err
+	}
+
+	errs := []error{}
+	for i, cmd := range cmds {
+		object, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		values[keys[i]] = object
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// Set defines data in Redis for given key identifier. The value is written
+// along with its tags in a transaction. As keys of different hash slots can't
+// be written in the same transaction, the cluster client runs a transaction
+// per hash slot.
 func (s *RedisClusterStore) Set(ctx context.Context, key any, value any, options ...Option) error {
 	opts := ApplyOptionsWithDefault(s.Options, options...)
 
-	err := s.Clusclient.Set(ctx, key.(string), value, opts.Expiration).Err()
-	if err != nil {
-		return err
+	if len(opts.Tags) == 0 {
+		return s.Clusclient.Set(ctx, key.(string), value, opts.Expiration).Err()
 	}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		s.setTags(ctx, key, tags)
+	_, err := s.Clusclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key.(string), value, opts.Expiration)
+		s.setTags(ctx, pipe, []string{key.(string)}, opts.Tags, s.storeOptions.tagTTL())
+		return nil
+	})
+	return err
+}
+
+// SetMany defines data in Redis for given key identifiers, along with their
+// tags, in a transaction per hash slot
+func (s *RedisClusterStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	if len(items) == 0 {
+		return nil
 	}
 
-	return nil
+	opts := ApplyOptionsWithDefault(s.Options, options...)
+
+	_, err := s.Clusclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		keys := make([]string, 0, len(items))
+		for key, value := range items {
+			pipe.Set(ctx, key.(string), value, opts.Expiration)
+			keys = append(keys, key.(string))
+		}
+		s.setTags(ctx, pipe, keys, opts.Tags, s.storeOptions.tagTTL())
+		return nil
+	})
+	return err
 }
 
-func (s *RedisClusterStore) setTags(ctx context.Context, key any, tags []string) {
+// setTags adds the given keys to the given tags using the given pipeline, the
+// indexes expiring after the given duration
+func (s *RedisClusterStore) setTags(ctx context.Context, pipe redis.Pipeliner, keys []string, tags []string, ttl time.Duration) {
+	if !s.storeOptions.TagIndexPerSlot {
+		setTags(ctx, pipe, keys, tags, ttl)
+		return
+	}
+
+	keysBySlot := map[int][]any{}
+	for _, key := range keys {
+		slot := RedisClusterKeySlot(key)
+		keysBySlot[slot] = append(keysBySlot[slot], key)
+	}
+
 	for _, tag := range tags {
-		tagKey := fmt.Sprintf(RedisTagPattern, tag)
-		s.Clusclient.SAdd(ctx, tagKey, key.(string))
-		s.Clusclient.Expire(ctx, tagKey, 720*time.Hour)
+		slots := make([]any, 0, len(keysBySlot))
+		for slot, slotKeys := range keysBySlot {
+			// The index belongs to the same slot as its keys, so that they
+			// are written in the same transaction
+			indexKey := tagIndexKey(tag, slot)
+			pipe.SAdd(ctx, indexKey, slotKeys...)
+			pipe.Expire(ctx, indexKey, ttl)
+
+			slots = append(slots, strconv.Itoa(slot))
+		}
+
+		// The registry of the slots belongs to another slot, so it is
+		// written in a separate transaction
+		slotsKey := fmt.Sprintf(RedisClusterTagSlotsPattern, tag)
+		pipe.SAdd(ctx, slotsKey, slots...)
+		pipe.Expire(ctx, slotsKey, ttl)
 	}
 }
 
+// tagIndexKey returns the key indexing the keys of the given tag belonging to
+// the given hash slot
+func tagIndexKey(tag string, slot int) string {
+	return fmt.Sprintf(RedisClusterTagIndexPattern, slotHashTag(slot), tag)
+}
+
 // Delete removes data from Redis for given key identifier
 func (s *RedisClusterStore) Delete(ctx context.Context, key any) error {
 	_, err := s.Clusclient.Del(ctx, key.(string)).Result()
 	return err
 }
 
-// Invalidate invalidates some cache data in Redis for given options
+// DeleteMany removes data from Redis for given key identifiers. Keys are
+// removed using a pipeline of DEL commands so that they can belong to
+// different hash slots.
+func (s *RedisClusterStore) DeleteMany(ctx context.Context, keys []any) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, err := s.Clusclient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key.(string))
+		}
+		return nil
+	})
+	return err
+}
+
+// Invalidate invalidates some cache data in Redis for given options. Tagged
+// keys are unlinked by batches, using a pipeline of UNLINK commands so that
+// they can belong to different hash slots. Keys matching a prefix, pattern or
+// predicate are scanned on each master node using SCAN MATCH.
 func (s *RedisClusterStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
+	errs := []error{}
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(RedisTagPattern, tag)
-			cacheKeys, err := s.Clusclient.SMembers(ctx, tagKey).Result()
+	if len(opts.Tags) > 0 {
+		var err error
+		if s.storeOptions.TagIndexPerSlot {
+			err = s.invalidateSlotTags(ctx, opts)
+		} else {
+			err = s.invalidateTags(ctx, opts)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.matchesKeys() {
+		if err := s.invalidateKeys(ctx, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *RedisClusterStore) invalidateTags(ctx context.Context, opts *InvalidateOptions) error {
+	tagsKeys, err := popTags(ctx, s.Clusclient.TxPipelined, opts.Tags)
+	if err != nil {
+		return err
+	}
+
+	batches := []unlinkBatch{}
+	for i, tag := range opts.Tags {
+		if len(tagsKeys[i]) == 0 {
+			opts.tagNotFound(tag)
+			continue
+		}
+
+		for _, key := range tagsKeys[i] {
+			batches = append(batches, unlinkBatch{tag: tag, keys: []string{key}})
+		}
+	}
+
+	return s.unlink(ctx, opts, batches)
+}
+
+// invalidateKeys unlinks the keys matching the prefix, pattern or predicate
+// of the given options, scanned by batches on each master node
+func (s *RedisClusterStore) invalidateKeys(ctx context.Context, opts *InvalidateOptions) error {
+	// Master nodes are scanned concurrently
+	mtx := &sync.Mutex{}
+	errs := []error{}
+
+	err := s.Clusclient.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		var cursor uint64
+		for {
+			keys, nextCursor, err := client.Scan(ctx, cursor, opts.scanPattern(), RedisClearBatchSize).Result()
 			if err != nil {
-				continue
+				return err
 			}
 
-			for _, cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
+			batches := make([]unlinkBatch, 0, len(keys))
+			for _, key := range keys {
+				if opts.matchKey(key) {
+					batches = append(batches, unlinkBatch{keys: []string{key}})
+				}
 			}
 
-			_ = s.Delete(ctx, tagKey)
+			mtx.Lock()
+			if err := s.unlink(ctx, opts, batches); err != nil {
+				errs = append(errs, err)
+			}
+			mtx.Unlock()
+
+			cursor = nextCursor
+			if cursor == 0 {
+				return nil
+			}
 		}
+	})
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// Clear resets all data in the store
-func (s *RedisClusterStore) Clear(ctx context.Context) error {
-	if err := s.Clusclient.FlushAll(ctx).Err(); err != nil {
+// unlink unlinks the given batches of keys, using pipelines of at most
+// RedisUnlinkBatchSize UNLINK commands so that they can belong to different
+// hash slots
+func (s *RedisClusterStore) unlink(ctx context.Context, opts *InvalidateOptions, batches []unlinkBatch) error {
+	errs := []error{}
+
+	for start := 0; start < len(batches); start += RedisUnlinkBatchSize {
+		end := start + RedisUnlinkBatchSize
+		if end > len(batches) {
+			end = len(batches)
+		}
+
+		if err := unlinkBatches(ctx, s.Clusclient.Pipelined, opts, batches[start:end]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// invalidateSlotTags removes the indexes of the given tags in each slot
+// registered for them, then unlinks their keys. Keys whose slot failed to be
+// registered are not found, see WithTagIndexPerSlot.
+func (s *RedisClusterStore) invalidateSlotTags(ctx context.Context, opts *InvalidateOptions) error {
+	tags := opts.Tags
+
+	slotsCmds := make([]*redis.StringSliceCmd, 0, len(tags))
+
+	_, err := s.Clusclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			slotsKey := fmt.Sprintf(RedisClusterTagSlotsPattern, tag)
+			slotsCmds = append(slotsCmds, pipe.SMembers(ctx, slotsKey))
+			pipe.Unlink(ctx, slotsKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Commands reading the keys of a tag in the index of a slot
+	type indexCmd struct {
+		tag string
+		cmd *redis.StringSliceCmd
+	}
+
+	indexCmds := []indexCmd{}
+
+	_, err = s.Clusclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, tag := range tags {
+			for _, member := range slotsCmds[i].Val() {
+				slot, err := strconv.Atoi(member)
+				if err != nil || slot < 0 || slot >= RedisClusterSlots {
+					continue
+				}
+
+				indexKey := tagIndexKey(tag, slot)
+				indexCmds = append(indexCmds, indexCmd{tag: tag, cmd: pipe.SMembers(ctx, indexKey)})
+				pipe.Unlink(ctx, indexKey)
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	// The keys of a tag in a slot are unlinked using multi-key commands
+	found := map[string]bool{}
+	batches := []unlinkBatch{}
+	for _, index := range indexCmds {
+		keys := index.cmd.Val()
+		if len(keys) > 0 {
+			found[index.tag] = true
+		}
+
+		for start := 0; start < len(keys); start += RedisUnlinkBatchSize {
+			end := start + RedisUnlinkBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			batches = append(batches, unlinkBatch{tag: index.tag, keys: keys[start:end]})
+		}
+	}
+
+	for _, tag := range tags {
+		if !found[tag] {
+			opts.tagNotFound(tag)
+		}
+	}
+
+	return unlinkBatches(ctx, s.Clusclient.Pipelined, opts, batches)
+}
+
+// Clear removes the keys starting with the clear prefix, scanned on each
+// master node using SCAN MATCH and unlinked by batches. When the store is
+// created using WithFlushOnClear, the whole Redis cluster is flushed instead.
+// Tag sets are kept, and expire along with their time to live.
+func (s *RedisClusterStore) Clear(ctx context.Context) error {
+	if s.storeOptions.FlushOnClear {
+		if err := s.Clusclient.FlushAll(ctx).Err(); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	if s.storeOptions.ClearPrefix == "" {
+		return ErrClearPrefixRequired
+	}
+
+	return s.invalidateKeys(ctx, &InvalidateOptions{Prefix: s.storeOptions.ClearPrefix})
 }
 
 // GetType returns the store type
@@ -0,0 +1,409 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prodadidb/gocache/store"
+)
+
+var errCrossSlot = errors.New("CROSSSLOT Keys in request don't hash to the same slot")
+
+// fakeCluster is a local stand-in of a Redis cluster: keys are spread over
+// several nodes by hash slot, commands involving keys of different slots fail
+// with a CROSSSLOT error, pipelines are split per node and transactions per
+// slot, as done by the go-redis cluster client.
+type fakeCluster struct {
+	mtx          sync.Mutex
+	nodes        []*fakeClusterNode
+	transactions int
+}
+
+type fakeClusterNode struct {
+	strings   map[string]string
+	sets      map[string]map[string]bool
+	pipelines int
+}
+
+// fakeClusterCommand is a command waiting to be run on the node of its keys
+type fakeClusterCommand struct {
+	cmd  redis.Cmder
+	keys []string
+	run  func(node *fakeClusterNode)
+}
+
+func newFakeCluster(nodes int) *fakeCluster {
+	c := &fakeCluster{}
+	for i := 0; i < nodes; i++ {
+		c.nodes = append(c.nodes, &fakeClusterNode{
+			strings: map[string]string{},
+			sets:    map[string]map[string]bool{},
+		})
+	}
+	return c
+}
+
+// keys returns the keys stored in the cluster, sorted
+func (c *fakeCluster) keys() []string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	keys := []string{}
+	for _, node := range c.nodes {
+		for key := range node.strings {
+			keys = append(keys, key)
+		}
+		for key := range node.sets {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (c *fakeCluster) resetCounters() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.transactions = 0
+	for _, node := range c.nodes {
+		node.pipelines = 0
+	}
+}
+
+func (c *fakeCluster) nodeOfSlot(slot int) *fakeClusterNode {
+	return c.nodes[slot*len(c.nodes)/store.RedisClusterSlots]
+}
+
+// slot returns the slot of the given command, or an error when its keys
+// belong to different slots
+func (c *fakeCluster) slot(command fakeClusterCommand) (int, error) {
+	slot := store.RedisClusterKeySlot(command.keys[0])
+	for _, key := range command.keys[1:] {
+		if store.RedisClusterKeySlot(key) != slot {
+			return 0, errCrossSlot
+		}
+	}
+	return slot, nil
+}
+
+func (c *fakeCluster) run(commands []fakeClusterCommand) error {
+	var firstErr error
+	for _, command := range commands {
+		slot, err := c.slot(command)
+		if err != nil {
+			command.cmd.SetErr(err)
+		} else {
+			command.run(c.nodeOfSlot(slot))
+		}
+		if err := command.cmd.Err(); err != nil && err != redis.Nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (c *fakeCluster) runOne(fn func(pipe *fakeClusterPipe)) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	pipe := &fakeClusterPipe{}
+	fn(pipe)
+	_ = c.run(pipe.commands)
+}
+
+func (c *fakeCluster) cmds(commands []fakeClusterCommand) []redis.Cmder {
+	cmds := make([]redis.Cmder, 0, len(commands))
+	for _, command := range commands {
+		cmds = append(cmds, command.cmd)
+	}
+	return cmds
+}
+
+func (c *fakeCluster) Get(ctx context.Context, key string) (cmd *redis.StringCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.Get(ctx, key) })
+	return cmd
+}
+
+func (c *fakeCluster) TTL(ctx context.Context, key string) *redis.DurationCmd {
+	cmd := redis.NewDurationCmd(ctx, time.Second, "ttl", key)
+	cmd.SetVal(-1)
+	return cmd
+}
+
+func (c *fakeCluster) Expire(ctx context.Context, key string, expiration time.Duration) (cmd *redis.BoolCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.Expire(ctx, key, expiration) })
+	return cmd
+}
+
+func (c *fakeCluster) Set(ctx context.Context, key string, value any, expiration time.Duration) (cmd *redis.StatusCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.Set(ctx, key, value, expiration) })
+	return cmd
+}
+
+func (c *fakeCluster) Del(ctx context.Context, keys ...string) (cmd *redis.IntCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.Del(ctx, keys...) })
+	return cmd
+}
+
+func (c *fakeCluster) FlushAll(ctx context.Context) *redis.StatusCmd {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, node := range c.nodes {
+		node.strings = map[string]string{}
+		node.sets = map[string]map[string]bool{}
+	}
+
+	cmd := redis.NewStatusCmd(ctx, "flushall")
+	cmd.SetVal("OK")
+	return cmd
+}
+
+func (c *fakeCluster) SAdd(ctx context.Context, key string, members ...any) (cmd *redis.IntCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.SAdd(ctx, key, members...) })
+	return cmd
+}
+
+func (c *fakeCluster) SMembers(ctx context.Context, key string) (cmd *redis.StringSliceCmd) {
+	c.runOne(func(pipe *fakeClusterPipe) { cmd = pipe.SMembers(ctx, key) })
+	return cmd
+}
+
+// ForEachMaster calls the given function with a client of each node, only
+// answering SCAN commands
+func (c *fakeCluster) ForEachMaster(ctx context.Context, fn func(ctx context.Context, client *redis.Client) error) error {
+	for _, node := range c.nodes {
+		client := redis.NewClient(&redis.Options{})
+		client.AddHook(fakeClusterNodeHook{cluster: c, node: node})
+
+		err := fn(ctx, client)
+		_ = client.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var errFakeClusterNode = errors.New("command answered by the fake cluster node")
+
+// fakeClusterNodeHook answers the commands sent to the client of a node
+// without reaching a server: commands are stopped before being processed,
+// then answered after
+type fakeClusterNodeHook struct {
+	cluster *fakeCluster
+	node    *fakeClusterNode
+}
+
+func (h fakeClusterNodeHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return ctx, errFakeClusterNode
+}
+
+func (h fakeClusterNodeHook) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	scanCmd, ok := cmd.(*redis.ScanCmd)
+	if !ok {
+		return fmt.Errorf("unexpected command %q on a fake cluster node", cmd.Name())
+	}
+
+	// SCAN cursor MATCH pattern COUNT count
+	pattern := fmt.Sprint(cmd.Args()[3])
+
+	h.cluster.mtx.Lock()
+	defer h.cluster.mtx.Unlock()
+
+	keys := []string{}
+	for key := range h.node.strings {
+		if store.MatchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range h.node.sets {
+		if store.MatchPattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	scanCmd.SetErr(nil)
+	scanCmd.SetVal(keys, 0)
+	return nil
+}
+
+func (h fakeClusterNodeHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, errFakeClusterNode
+}
+
+func (h fakeClusterNodeHook) AfterProcessPipeline(_ context.Context, _ []redis.Cmder) error {
+	return errFakeClusterNode
+}
+
+// Pipelined runs the commands of the given function in a pipeline per node
+func (c *fakeCluster) Pipelined(_ context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	pipe := &fakeClusterPipe{}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+
+	commandsByNode := map[*fakeClusterNode][]fakeClusterCommand{}
+	nodes := []*fakeClusterNode{}
+	for _, command := range pipe.commands {
+		node := c.nodeOfSlot(store.RedisClusterKeySlot(command.keys[0]))
+		if _, ok := commandsByNode[node]; !ok {
+			nodes = append(nodes, node)
+		}
+		commandsByNode[node] = append(commandsByNode[node], command)
+	}
+
+	var firstErr error
+	for _, node := range nodes {
+		node.pipelines++
+		if err := c.run(commandsByNode[node]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return c.cmds(pipe.commands), firstErr
+}
+
+// TxPipelined runs the commands of the given function in a transaction per
+// slot
+func (c *fakeCluster) TxPipelined(_ context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	pipe := &fakeClusterPipe{}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+
+	commandsBySlot := map[int][]fakeClusterCommand{}
+	slots := []int{}
+	for _, command := range pipe.commands {
+		slot := store.RedisClusterKeySlot(command.keys[0])
+		if _, ok := commandsBySlot[slot]; !ok {
+			slots = append(slots, slot)
+		}
+		commandsBySlot[slot] = append(commandsBySlot[slot], command)
+	}
+
+	var firstErr error
+	for _, slot := range slots {
+		c.transactions++
+		if err := c.run(commandsBySlot[slot]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return c.cmds(pipe.commands), firstErr
+}
+
+// fakeClusterPipe records the commands sent to the fake cluster
+type fakeClusterPipe struct {
+	redis.Pipeliner
+
+	commands []fakeClusterCommand
+}
+
+func (p *fakeClusterPipe) add(cmd redis.Cmder, keys []string, run func(node *fakeClusterNode)) {
+	p.commands = append(p.commands, fakeClusterCommand{cmd: cmd, keys: keys, run: run})
+}
+
+func (p *fakeClusterPipe) Get(ctx context.Context, key string) *redis.StringCmd {
+	cmd := redis.NewStringCmd(ctx, "get", key)
+	p.add(cmd, []string{key}, func(node *fakeClusterNode) {
+		value, ok := node.strings[key]
+		if !ok {
+			cmd.SetErr(redis.Nil)
+			return
+		}
+		cmd.SetVal(value)
+	})
+	return cmd
+}
+
+func (p *fakeClusterPipe) Set(ctx context.Context, key string, value any, _ time.Duration) *redis.StatusCmd {
+	cmd := redis.NewStatusCmd(ctx, "set", key, value)
+	p.add(cmd, []string{key}, func(node *fakeClusterNode) {
+		if bytes, ok := value.([]byte); ok {
+			value = string(bytes)
+		}
+		delete(node.sets, key)
+		node.strings[key] = fmt.Sprint(value)
+		cmd.SetVal("OK")
+	})
+	return cmd
+}
+
+func (p *fakeClusterPipe) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	return p.remove(ctx, "del", keys)
+}
+
+func (p *fakeClusterPipe) Unlink(ctx context.Context, keys ...string) *redis.IntCmd {
+	return p.remove(ctx, "unlink", keys)
+}
+
+func (p *fakeClusterPipe) remove(ctx context.Context, name string, keys []string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx, name)
+	p.add(cmd, keys, func(node *fakeClusterNode) {
+		removed := 0
+		for _, key := range keys {
+			_, isString := node.strings[key]
+			_, isSet := node.sets[key]
+			if isString || isSet {
+				removed++
+			}
+			delete(node.strings, key)
+			delete(node.sets, key)
+		}
+		cmd.SetVal(int64(removed))
+	})
+	return cmd
+}
+
+func (p *fakeClusterPipe) SAdd(ctx context.Context, key string, members ...any) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx, "sadd", key)
+	p.add(cmd, []string{key}, func(node *fakeClusterNode) {
+		if _, ok := node.sets[key]; !ok {
+			node.sets[key] = map[string]bool{}
+		}
+		for _, member := range members {
+			node.sets[key][fmt.Sprint(member)] = true
+		}
+		cmd.SetVal(int64(len(members)))
+	})
+	return cmd
+}
+
+func (p *fakeClusterPipe) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(ctx, "smembers", key)
+	p.add(cmd, []string{key}, func(node *fakeClusterNode) {
+		members := []string{}
+		for member := range node.sets[key] {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		cmd.SetVal(members)
+	})
+	return cmd
+}
+
+func (p *fakeClusterPipe) Expire(ctx context.Context, key string, _ time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx, "expire", key)
+	p.add(cmd, []string{key}, func(node *fakeClusterNode) {
+		_, isString := node.strings[key]
+		_, isSet := node.sets[key]
+		cmd.SetVal(isString || isSet)
+	})
+	return cmd
+}
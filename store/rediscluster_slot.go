@@ -0,0 +1,62 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RedisClusterSlots is the number of hash slots of a Redis cluster
+const RedisClusterSlots = 16384
+
+var (
+	slotHashTags     [RedisClusterSlots]string
+	slotHashTagsOnce sync.Once
+)
+
+// RedisClusterKeySlot returns the hash slot of the given key in a Redis
+// cluster: the CRC16 of the key (or of its hash tag, the part between the
+// first "{" and the next "}" when not empty) modulo the number of slots.
+func RedisClusterKeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+
+	return int(crc16(key) % RedisClusterSlots)
+}
+
+// slotHashTag returns a hash tag of the given slot: a key containing it
+// belongs to the slot whatever the rest of the key
+func slotHashTag(slot int) string {
+	slotHashTagsOnce.Do(func() {
+		found := 0
+		for i := 0; found < RedisClusterSlots; i++ {
+			hashTag := strconv.FormatInt(int64(i), 36)
+			if slot := crc16(hashTag) % RedisClusterSlots; slotHashTags[slot] == "" {
+				slotHashTags[slot] = hashTag
+				found++
+			}
+		}
+	})
+
+	return slotHashTags[slot]
+}
+
+// crc16 returns the CRC16 (XMODEM) of the given string, as used by Redis
+// cluster to compute hash slots
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
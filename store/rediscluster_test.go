@@ -2,6 +2,9 @@ package store_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -96,10 +99,10 @@ func TestRedisClusterSetWithTags(t *testing.T) {
 	cacheKey := "my-key"
 	cacheValue := "my-cache-value"
 
+	pipe := newFakePipeliner(nil)
+
 	client := NewMockRedisClusterClientInterface(ctrl)
-	client.EXPECT().Set(ctx, cacheKey, cacheValue, time.Duration(0)).Return(&redis.StatusCmd{})
-	client.EXPECT().SAdd(ctx, "gocache_tag_tag1", "my-key").Return(&redis.IntCmd{})
-	client.EXPECT().Expire(ctx, "gocache_tag_tag1", 720*time.Hour).Return(&redis.BoolCmd{})
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
 
 	s := store.NewRedisCluster(client)
 
@@ -108,6 +111,50 @@ func TestRedisClusterSetWithTags(t *testing.T) {
 
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"my-key": cacheValue}, pipe.sets)
+	assert.Equal(t, map[string][]any{"gocache_tag_tag1": {"my-key"}}, pipe.sadds)
+	assert.Equal(t, map[string]time.Duration{"gocache_tag_tag1": 720 * time.Hour}, pipe.expires)
+}
+
+func TestRedisClusterSetWithTagsWhenTagTTL(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(nil)
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
+
+	s := store.NewRedisCluster(client, store.WithTagTTL(time.Hour))
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"gocache_tag_tag1": time.Hour}, pipe.expires)
+}
+
+func TestRedisClusterSetWithTagsWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("EXECABORT Transaction discarded because of previous errors")
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).Return(nil, expectedErr)
+
+	s := store.NewRedisCluster(client)
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Equal(t, expectedErr, err)
 }
 
 func TestRedisClusterDelete(t *testing.T) {
@@ -136,19 +183,47 @@ func TestRedisClusterInvalidate(t *testing.T) {
 
 	ctx := context.Background()
 
-	cacheKeys := &redis.StringSliceCmd{}
+	tx := newFakePipeliner(nil)
+	tx.members["gocache_tag_tag1"] = []string{"key-1", "key-2"}
+	tx.members["gocache_tag_tag2"] = []string{"key-3"}
+
+	pipe := newFakePipeliner(nil)
 
 	client := NewMockRedisClusterClientInterface(ctrl)
-	client.EXPECT().SMembers(ctx, "gocache_tag_tag1").Return(cacheKeys)
-	client.EXPECT().Del(ctx, "gocache_tag_tag1").Return(&redis.IntCmd{})
+	gomock.InOrder(
+		client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(tx.run),
+		client.EXPECT().Pipelined(ctx, gomock.Any()).DoAndReturn(pipe.run),
+	)
 
 	s := store.NewRedisCluster(client)
 
 	// When
-	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2"}))
 
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"gocache_tag_tag1"}, {"gocache_tag_tag2"}}, tx.unlinks)
+	assert.Equal(t, [][]string{{"key-1"}, {"key-2"}, {"key-3"}}, pipe.unlinks)
+}
+
+func TestRedisClusterInvalidateWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to read tags")
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).Return(nil, expectedErr)
+
+	s := store.NewRedisCluster(client)
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+
+	// Then
+	assert.ErrorIs(t, err, expectedErr)
 }
 
 func TestRedisClusterClear(t *testing.T) {
@@ -160,7 +235,7 @@ func TestRedisClusterClear(t *testing.T) {
 	client := NewMockRedisClusterClientInterface(ctrl)
 	client.EXPECT().FlushAll(ctx).Return(&redis.StatusCmd{})
 
-	s := store.NewRedisCluster(client)
+	s := store.NewRedisCluster(client, store.WithFlushOnClear())
 
 	// When
 	err := s.Clear(ctx)
@@ -169,6 +244,49 @@ func TestRedisClusterClear(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRedisClusterClearWhenClearPrefix(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster, store.WithClearPrefix("my-app:"))
+
+	err := s.SetMany(ctx, map[any]any{
+		"my-app:1":   "value-1",
+		"my-app:2":   "value-2",
+		"my-app:3":   "value-3",
+		"other-app:": "value-4",
+	})
+	assert.Nil(t, err)
+
+	// When
+	err = s.Clear(ctx)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"other-app:"}, cluster.keys())
+}
+
+func TestRedisClusterClearWhenNoClearPrefix(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster)
+
+	err := s.Set(ctx, "my-key", "my-value")
+	assert.Nil(t, err)
+
+	// When
+	err = s.Clear(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, store.ErrClearPrefixRequired)
+	assert.Equal(t, []string{"my-key"}, cluster.keys())
+}
+
 func TestRedisClusterGetType(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -180,3 +298,262 @@ func TestRedisClusterGetType(t *testing.T) {
 	// When - Then
 	assert.Equal(t, store.RedisClusterType, s.GetType())
 }
+
+func TestRedisClusterGetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(map[string]string{"key-1": "value-1"})
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().Pipelined(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+		_, _ = pipe.run(ctx, fn)
+		return nil, redis.Nil
+	})
+
+	s := store.NewRedisCluster(client)
+
+	// When
+	values, err := s.GetMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[any]any{"key-1": "value-1"}, values)
+}
+
+func TestRedisClusterSetMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(nil)
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().TxPipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
+
+	s := store.NewRedisCluster(client)
+
+	// When
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	}, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"key-1": "value-1", "key-2": "value-2"}, pipe.sets)
+	assert.ElementsMatch(t, []any{"key-1", "key-2"}, pipe.sadds["gocache_tag_tag1"])
+}
+
+func TestRedisClusterDeleteMany(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	pipe := newFakePipeliner(nil)
+
+	client := NewMockRedisClusterClientInterface(ctrl)
+	client.EXPECT().Pipelined(ctx, gomock.Any()).DoAndReturn(pipe.run)
+
+	s := store.NewRedisCluster(client)
+
+	// When
+	err := s.DeleteMany(ctx, []any{"key-1", "key-2"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1", "key-2"}, pipe.dels)
+}
+
+func TestRedisClusterKeySlot(t *testing.T) {
+	// When - Then
+	assert.Equal(t, 12182, store.RedisClusterKeySlot("foo"))
+	assert.Equal(t, 12739, store.RedisClusterKeySlot("123456789"))
+	assert.Equal(t, store.RedisClusterKeySlot("user1000"), store.RedisClusterKeySlot("{user1000}.following"))
+	assert.Equal(t, store.RedisClusterKeySlot("{user1000}.following"), store.RedisClusterKeySlot("{user1000}.followers"))
+	assert.NotEqual(t, store.RedisClusterKeySlot("{}foo"), store.RedisClusterKeySlot("foo"))
+}
+
+func TestRedisClusterSetWhenTagIndexPerSlot(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster, store.WithTagIndexPerSlot())
+
+	// When
+	err := s.Set(ctx, "my-key", "my-cache-value", store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+
+	indexKeys := []string{}
+	for _, key := range cluster.keys() {
+		if strings.HasPrefix(key, "gocache_tag_{") {
+			indexKeys = append(indexKeys, key)
+		}
+	}
+
+	assert.Len(t, indexKeys, 1)
+	assert.True(t, strings.HasSuffix(indexKeys[0], "_tag1"))
+	assert.Equal(t, store.RedisClusterKeySlot("my-key"), store.RedisClusterKeySlot(indexKeys[0]))
+
+	value, err := s.Get(ctx, "my-key")
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cache-value", value)
+}
+
+func TestRedisClusterInvalidateWhenTagIndexPerSlot(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster, store.WithTagIndexPerSlot(), store.WithFlushOnClear())
+
+	items := map[any]any{}
+	for i := 0; i < 50; i++ {
+		items[fmt.Sprintf("book-%d", i)] = "value"
+	}
+
+	err := s.SetMany(ctx, items, store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	err = s.Set(ctx, "movie-1", "value", store.WithTags([]string{"movie"}))
+	assert.Nil(t, err)
+
+	cluster.resetCounters()
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidateTags([]string{"book"}))
+
+	// Then
+	assert.Nil(t, err)
+
+	keys := cluster.keys()
+	assert.Len(t, keys, 3)
+	assert.Contains(t, keys, "movie-1")
+	assert.Contains(t, keys, "gocache_tag_slots_movie")
+
+	for _, node := range cluster.nodes {
+		assert.Equal(t, 1, node.pipelines)
+	}
+}
+
+func TestRedisClusterInvalidateResultWhenTagIndexPerSlot(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster, store.WithTagIndexPerSlot(), store.WithFlushOnClear())
+
+	items := map[any]any{}
+	for i := 0; i < 20; i++ {
+		items[fmt.Sprintf("book-%d", i)] = "value"
+	}
+
+	err := s.SetMany(ctx, items, store.WithTags([]string{"book"}))
+	assert.Nil(t, err)
+
+	err = s.Set(ctx, "movie-1", "value", store.WithTags([]string{"movie"}))
+	assert.Nil(t, err)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidateTags([]string{"book", "movie", "music"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, store.InvalidateResult{
+		Removed:      21,
+		RemovedByTag: map[string]int{"book": 20, "movie": 1},
+		TagsNotFound: []string{"music"},
+	}, *result)
+}
+
+func TestRedisClusterInvalidateWhenFakeCluster(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster)
+
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+		"key-3": "value-3",
+	}, store.WithTags([]string{"tag1"}))
+	assert.Nil(t, err)
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Empty(t, cluster.keys())
+}
+
+func TestRedisClusterInvalidateResultWhenFakeCluster(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster)
+
+	err := s.SetMany(ctx, map[any]any{
+		"key-1": "value-1",
+		"key-2": "value-2",
+	}, store.WithTags([]string{"tag1"}))
+	assert.Nil(t, err)
+
+	err = s.Set(ctx, "key-3", "value-3", store.WithTags([]string{"tag1", "tag2"}))
+	assert.Nil(t, err)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1", "tag2", "tag3"}), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, store.InvalidateResult{
+		Removed:      3,
+		RemovedByTag: map[string]int{"tag1": 3, "tag2": 0},
+		TagsNotFound: []string{"tag3"},
+	}, *result)
+}
+
+func TestRedisClusterInvalidateByPrefixWhenFakeCluster(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	cluster := newFakeCluster(3)
+
+	s := store.NewRedisCluster(cluster)
+
+	err := s.SetMany(ctx, map[any]any{
+		"user:1":    "value-1",
+		"user:2":    "value-2",
+		"product:1": "value-3",
+	})
+	assert.Nil(t, err)
+
+	result := &store.InvalidateResult{}
+
+	// When
+	err = s.Invalidate(ctx, store.WithInvalidatePrefix("user:"), store.WithInvalidateResult(result))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Removed)
+	assert.Equal(t, []string{"product:1"}, cluster.keys())
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -27,16 +26,22 @@ type RistrettoClientInterface interface {
 
 // RistrettoStore is a store for Ristretto (memory) library
 type RistrettoStore struct {
-	Client  RistrettoClientInterface
-	Options *Options
+	Client   RistrettoClientInterface
+	Options  *Options
+	TagIndex TagIndex
 }
 
 // NewRistretto creates a new store to Ristretto (memory) library instance
-func NewRistretto(client RistrettoClientInterface, options ...Option) *RistrettoStore {
-	return &RistrettoStore{
+func NewRistretto(client RistrettoClientInterface, options ...StoreOption) *RistrettoStore {
+	storeOptions := applyStoreOptions(options...)
+
+	s := &RistrettoStore{
 		Client:  client,
-		Options: applyOptions(options...),
+		Options: applyOptions(storeOptions.DefaultOptions...),
 	}
+	s.TagIndex = storeOptions.tagIndex(ristrettoTagStorage{store: s})
+
+	return s
 }
 
 // Get returns data stored from a given key
@@ -57,6 +62,11 @@ func (s *RistrettoStore) GetWithTTL(ctx context.Context, key any) (any, time.Dur
 	return value, 0, err
 }
 
+// GetMany returns data stored from given keys
+func (s *RistrettoStore) GetMany(ctx context.Context, keys []any) (map[any]any, error) {
+	return GetManyFallback(ctx, s, keys)
+}
+
 // Set defines data in Ristretto memoey cache for given key identifier
 func (s *RistrettoStore) Set(ctx context.Context, key any, value any, options ...Option) error {
 	opts := ApplyOptionsWithDefault(s.Options, options...)
@@ -72,37 +82,15 @@ func (s *RistrettoStore) Set(ctx context.Context, key any, value any, options ..
 	}
 
 	if tags := opts.Tags; len(tags) > 0 {
-		s.setTags(ctx, key, tags)
+		return s.TagIndex.Add(ctx, key.(string), opts.Expiration, tags)
 	}
 
 	return nil
 }
 
-func (s *RistrettoStore) setTags(ctx context.Context, key any, tags []string) {
-	for _, tag := range tags {
-		tagKey := fmt.Sprintf(RistrettoTagPattern, tag)
-		cacheKeys := []string{}
-
-		if result, err := s.Get(ctx, tagKey); err == nil {
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
-			}
-		}
-
-		alreadyInserted := false
-		for _, cacheKey := range cacheKeys {
-			if cacheKey == key.(string) {
-				alreadyInserted = true
-				break
-			}
-		}
-
-		if !alreadyInserted {
-			cacheKeys = append(cacheKeys, key.(string))
-		}
-
-		_ = s.Set(ctx, tagKey, []byte(strings.Join(cacheKeys, ",")), WithExpiration(720*time.Hour))
-	}
+// SetMany defines data for given key identifiers
+func (s *RistrettoStore) SetMany(ctx context.Context, items map[any]any, options ...Option) error {
+	return SetManyFallback(ctx, s, items, options...)
 }
 
 // Delete removes data in Ristretto memoey cache for given key identifier
@@ -111,30 +99,42 @@ func (s *RistrettoStore) Delete(_ context.Context, key any) error {
 	return nil
 }
 
-// Invalidate invalidates some cache data in Redis for given options
+// DeleteMany removes data for given key identifiers
+func (s *RistrettoStore) DeleteMany(ctx context.Context, keys []any) error {
+	return DeleteManyFallback(ctx, s, keys)
+}
+
+// Invalidate invalidates some cache data in Ristretto for given options. As
+// Ristretto can't list its keys, keys can't be invalidated by prefix, pattern
+// or predicate.
 func (s *RistrettoStore) Invalidate(ctx context.Context, options ...InvalidateOption) error {
 	opts := applyInvalidateOptions(options...)
 
-	if tags := opts.Tags; len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := fmt.Sprintf(RistrettoTagPattern, tag)
-			result, err := s.Get(ctx, tagKey)
-			if err != nil {
-				return nil
-			}
-
-			cacheKeys := []string{}
-			if bytes, ok := result.([]byte); ok {
-				cacheKeys = strings.Split(string(bytes), ",")
-			}
-
-			for _, cacheKey := range cacheKeys {
-				_ = s.Delete(ctx, cacheKey)
-			}
+	if opts.matchesKeys() {
+		return fmt.Errorf("%w: %s can't list its keys", ErrUnsupportedInvalidateOption, RistrettoType)
+	}
+
+	errs := []error{}
+
+	for _, tag := range opts.Tags {
+		cacheKeys, err := s.TagIndex.Pop(ctx, tag)
+		if err == nil {
+			err = opts.deleteTagKeys(tag, cacheKeys, s.deleteKey)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate tag %q: %w", tag, err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// deleteKey deletes the given key, returning whether it existed
+func (s *RistrettoStore) deleteKey(key string) (bool, error) {
+	_, found := s.Client.Get(key)
+	s.Client.Del(key)
+
+	return found, nil
 }
 
 // Clear resets all data in the store
@@ -147,3 +147,31 @@ func (s *RistrettoStore) Clear(_ context.Context) error {
 func (s *RistrettoStore) GetType() string {
 	return RistrettoType
 }
+
+// ristrettoTagStorage stores the tag index of a RistrettoStore in Ristretto
+type ristrettoTagStorage struct {
+	store *RistrettoStore
+}
+
+func (t ristrettoTagStorage) LoadTag(_ context.Context, tag string) ([]byte, any, error) {
+	value, exists := t.store.Client.Get(fmt.Sprintf(RistrettoTagPattern, tag))
+	if !exists {
+		return nil, nil, nil
+	}
+
+	members, _ := value.([]byte)
+	return members, nil, nil
+}
+
+func (t ristrettoTagStorage) StoreTag(_ context.Context, tag string, members []byte, _ any, expiration time.Duration) error {
+	if set := t.store.Client.SetWithTTL(fmt.Sprintf(RistrettoTagPattern, tag), members, t.store.Options.Cost, expiration); !set {
+		return fmt.Errorf("An error has occurred while setting the index of tag '%v'", tag)
+	}
+
+	return nil
+}
+
+func (t ristrettoTagStorage) DeleteTag(_ context.Context, tag string) error {
+	t.store.Client.Del(fmt.Sprintf(RistrettoTagPattern, tag))
+	return nil
+}
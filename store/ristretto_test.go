@@ -2,6 +2,7 @@ package store_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -188,8 +189,8 @@ func TestRistrettoSetWithTags(t *testing.T) {
 
 	client := NewMockRistrettoClientInterface(ctrl)
 	client.EXPECT().SetWithTTL(cacheKey, cacheValue, int64(0), 0*time.Second).Return(true)
-	client.EXPECT().Get("gocache_tag_tag1").Return(nil, true)
-	client.EXPECT().SetWithTTL("gocache_tag_tag1", []byte("my-key"), int64(0), 720*time.Hour).Return(true)
+	client.EXPECT().Get("gocache_tag_tag1").Return(nil, false)
+	client.EXPECT().SetWithTTL("gocache_tag_tag1", matchTagMembers("my-key"), int64(0), 720*time.Hour).Return(true)
 
 	s := store.NewRistretto(client)
 
@@ -211,8 +212,8 @@ func TestRistrettoSetWithTagsWhenAlreadyInserted(t *testing.T) {
 
 	client := NewMockRistrettoClientInterface(ctrl)
 	client.EXPECT().SetWithTTL(cacheKey, cacheValue, int64(0), 0*time.Second).Return(true)
-	client.EXPECT().Get("gocache_tag_tag1").Return([]byte("my-key,a-second-key"), true)
-	client.EXPECT().SetWithTTL("gocache_tag_tag1", []byte("my-key,a-second-key"), int64(0), 720*time.Hour).Return(true)
+	client.EXPECT().Get("gocache_tag_tag1").Return(tagMembers("my-key", "a-second-key"), true)
+	client.EXPECT().SetWithTTL("gocache_tag_tag1", matchTagMembers("my-key", "a-second-key"), int64(0), 720*time.Hour).Return(true)
 
 	s := store.NewRistretto(client)
 
@@ -223,6 +224,52 @@ func TestRistrettoSetWithTagsWhenAlreadyInserted(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRistrettoSetWithTagsWhenTagTTL(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockRistrettoClientInterface(ctrl)
+	client.EXPECT().SetWithTTL(cacheKey, cacheValue, int64(0), 0*time.Second).Return(true)
+	client.EXPECT().Get("gocache_tag_tag1").Return(nil, false)
+	client.EXPECT().SetWithTTL("gocache_tag_tag1", matchTagMembers("my-key"), int64(0), time.Hour).Return(true)
+
+	s := store.NewRistretto(client, store.WithTagTTL(time.Hour))
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestRistrettoSetWithTagsWhenError(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	cacheKey := "my-key"
+	cacheValue := []byte("my-cache-value")
+
+	client := NewMockRistrettoClientInterface(ctrl)
+	client.EXPECT().SetWithTTL(cacheKey, cacheValue, int64(0), 0*time.Second).Return(true)
+	client.EXPECT().Get("gocache_tag_tag1").Return(nil, false)
+	client.EXPECT().SetWithTTL("gocache_tag_tag1", matchTagMembers("my-key"), int64(0), 720*time.Hour).Return(false)
+
+	s := store.NewRistretto(client)
+
+	// When
+	err := s.Set(ctx, cacheKey, cacheValue, store.WithTags([]string{"tag1"}))
+
+	// Then
+	assert.ErrorContains(t, err, "An error has occurred while setting the index of tag 'tag1'")
+}
+
 func TestRistrettoDelete(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -249,20 +296,26 @@ func TestRistrettoInvalidate(t *testing.T) {
 
 	ctx := context.Background()
 
-	cacheKeys := []byte("a23fdf987h2svc23,jHG2372x38hf74")
+	cacheKeys := tagMembers("a23fdf987h2svc23", "jHG2372x38hf74")
 
 	client := NewMockRistrettoClientInterface(ctrl)
 	client.EXPECT().Get("gocache_tag_tag1").Return(cacheKeys, true)
+	client.EXPECT().SetWithTTL("gocache_tag_tag1", tagMembers(), int64(0), 720*time.Hour).Return(true)
+	client.EXPECT().Get("a23fdf987h2svc23").Return([]byte("value"), true)
 	client.EXPECT().Del("a23fdf987h2svc23")
+	client.EXPECT().Get("jHG2372x38hf74").Return(nil, false)
 	client.EXPECT().Del("jHG2372x38hf74")
 
 	s := store.NewRistretto(client)
 
+	result := &store.InvalidateResult{}
+
 	// When
-	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}))
+	err := s.Invalidate(ctx, store.WithInvalidateTags([]string{"tag1"}), store.WithInvalidateResult(result))
 
 	// Then
 	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"tag1": 1}, result.RemovedByTag)
 }
 
 func TestRistrettoInvalidateWhenError(t *testing.T) {
@@ -285,6 +338,23 @@ func TestRistrettoInvalidateWhenError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestRistrettoInvalidateByPattern(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	ctx := context.Background()
+
+	client := NewMockRistrettoClientInterface(ctrl)
+
+	s := store.NewRistretto(client)
+
+	// When
+	err := s.Invalidate(ctx, store.WithInvalidatePattern("user:*"))
+
+	// Then
+	assert.True(t, errors.Is(err, store.ErrUnsupportedInvalidateOption))
+}
+
 func TestRistrettoClear(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
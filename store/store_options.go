@@ -0,0 +1,149 @@
+package store
+
+import (
+	"time"
+)
+
+// DefaultGenerationTTL is the default time during which a Memcache store keeps
+// the generation of its clear scope locally
+const DefaultGenerationTTL = time.Second
+
+// StoreOption represents an option given when creating a store: either a
+// store setting (WithClearPrefix, WithTagTTL, ...) or an Option used by
+// default when setting values (WithExpiration, ...).
+type StoreOption interface {
+	applyStoreOption(o *StoreOptions)
+}
+
+// StoreSetting represents a store setting option function.
+type StoreSetting func(o *StoreOptions)
+
+func (s StoreSetting) applyStoreOption(o *StoreOptions) {
+	s(o)
+}
+
+func (opt Option) applyStoreOption(o *StoreOptions) {
+	o.DefaultOptions = append(o.DefaultOptions, opt)
+}
+
+// StoreOptions are the settings of a store, given when creating it
+type StoreOptions struct {
+	ClearScope    string
+	ClearPrefix   string
+	FlushOnClear  bool
+	GenerationTTL time.Duration
+
+	TagIndexPerSlot bool
+
+	TagTTL   time.Duration
+	TagIndex TagIndex
+
+	DefaultOptions []Option
+}
+
+func applyStoreOptions(opts ...StoreOption) *StoreOptions {
+	o := &StoreOptions{}
+
+	for _, opt := range opts {
+		opt.applyStoreOption(o)
+	}
+
+	return o
+}
+
+// WithClearScope allows to specify the scope of the values removed by Clear on
+// Memcache stores, so that several applications sharing a server only remove
+// their own values. Keys are then prefixed with the generation of the scope,
+// so values written without a scope are not read anymore.
+func WithClearScope(scope string) StoreSetting {
+	return func(o *StoreOptions) {
+		o.ClearScope = scope
+	}
+}
+
+// WithGenerationTTL allows to specify the time during which a Memcache store
+// created using WithClearScope keeps the generation of its scope locally.
+// Other stores sharing the scope stop returning cleared values within this
+// delay. Defaults to DefaultGenerationTTL.
+func WithGenerationTTL(ttl time.Duration) StoreSetting {
+	return func(o *StoreOptions) {
+		o.GenerationTTL = ttl
+	}
+}
+
+// WithClearPrefix allows to specify the prefix of the keys removed by Clear on
+// Redis and Redis cluster stores, so that several applications sharing a
+// server only remove their own values.
+func WithClearPrefix(prefix string) StoreSetting {
+	return func(o *StoreOptions) {
+		o.ClearPrefix = prefix
+	}
+}
+
+// WithFlushOnClear allows Clear to flush the whole server instead of only
+// removing the values of the clear scope or prefix (Redis, Redis cluster and
+// Memcache). It removes the values of every other application sharing the
+// server.
+func WithFlushOnClear() StoreSetting {
+	return func(o *StoreOptions) {
+		o.FlushOnClear = true
+	}
+}
+
+// WithTagIndexPerSlot allows Redis cluster stores to index the keys of a tag
+// per hash slot, in sets belonging to the same slot as the keys they index.
+// Values are then written along with the index of their slot atomically, and
+// tags are invalidated without cross-slot commands. The slots of a tag are
+// registered in another slot, written in a separate transaction: when it
+// fails, the value is indexed but not invalidated along with its tag, and
+// expires along with its own TTL.
+func WithTagIndexPerSlot() StoreSetting {
+	return func(o *StoreOptions) {
+		o.TagIndexPerSlot = true
+	}
+}
+
+// WithTagTTL allows to specify the time to live of the index of each tag,
+// refreshed each time a value is tagged (Bigcache, Freecache, GoCache,
+// Memcache, Pegasus, Redis, Redis cluster and Ristretto). Defaults to
+// DefaultTagTTL.
+func WithTagTTL(ttl time.Duration) StoreSetting {
+	return func(o *StoreOptions) {
+		o.TagTTL = ttl
+	}
+}
+
+// WithTagIndex allows to specify the index keeping the keys of each tag on
+// stores keeping it as a value (Bigcache, Freecache, Memcache, Pegasus and
+// Ristretto), instead of an index stored along with the values.
+func WithTagIndex(index TagIndex) StoreSetting {
+	return func(o *StoreOptions) {
+		o.TagIndex = index
+	}
+}
+
+// generationTTL returns the time during which the generation of a clear scope
+// is kept locally
+func (o *StoreOptions) generationTTL() time.Duration {
+	if o.GenerationTTL <= 0 {
+		return DefaultGenerationTTL
+	}
+	return o.GenerationTTL
+}
+
+// tagTTL returns the time to live of the index of each tag
+func (o *StoreOptions) tagTTL() time.Duration {
+	if o.TagTTL <= 0 {
+		return DefaultTagTTL
+	}
+	return o.TagTTL
+}
+
+// tagIndex returns the index keeping the keys of each tag: the given one or
+// a StoreTagIndex using the given storage
+func (o *StoreOptions) tagIndex(storage TagIndexStorage) TagIndex {
+	if o.TagIndex != nil {
+		return o.TagIndex
+	}
+	return NewTagIndex(storage, o.tagTTL())
+}
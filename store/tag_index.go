@@ -0,0 +1,434 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTagTTL is the default time to live of the index of a tag
+	DefaultTagTTL = 720 * time.Hour
+	// TagIndexMaxAttempts is the number of attempts to update the index of a
+	// tag modified concurrently by another client
+	TagIndexMaxAttempts = 5
+	// DefaultTagIndexChunkSize is the default number of keys of a tag stored
+	// in a single value
+	DefaultTagIndexChunkSize = 1000
+
+	// tagIndexLocks is the number of locks serializing the updates of the
+	// tags, each tag being updated under one of them
+	tagIndexLocks = 64
+)
+
+const (
+	// tagIndexHeader prefixes the encoded members of a tag, telling them
+	// apart from the comma-joined keys written by previous versions
+	tagIndexHeader = "\x00gct\x01"
+	// tagIndexChunkedHeader prefixes the encoded members of a tag having
+	// overflow chunks, followed by the range of these chunks
+	tagIndexChunkedHeader = "\x00gct\x02"
+	// tagIndexChunkPattern is the pattern of the name of an overflow chunk of
+	// a tag
+	tagIndexChunkPattern = "%s:gocache_chunk_%d"
+)
+
+var (
+	// ErrTagIndexConflict is returned by a TagIndexStorage when the index of a
+	// tag has been modified since it was loaded
+	ErrTagIndexConflict = errors.New("tag index modified concurrently")
+	// ErrInvalidTagIndex is returned when the index of a tag can't be decoded
+	ErrInvalidTagIndex = errors.New("invalid tag index")
+)
+
+// TagIndex keeps the keys associated with each tag of a store, so that they
+// can be invalidated
+type TagIndex interface {
+	// Add associates the given key, expiring after the given duration (or
+	// never when 0), with the given tags
+	Add(ctx context.Context, key string, expiration time.Duration, tags []string) error
+	// Pop removes the given tag and returns the keys associated with it
+	Pop(ctx context.Context, tag string) ([]string, error)
+}
+
+// TagIndexStorage stores the encoded members of the tags of a StoreTagIndex
+type TagIndexStorage interface {
+	// LoadTag returns the encoded members of the given tag (nil when missing)
+	// and a token given back to StoreTag
+	LoadTag(ctx context.Context, tag string) ([]byte, any, error)
+	// StoreTag writes the encoded members of the given tag, expiring after
+	// the given duration. It returns ErrTagIndexConflict when the tag has
+	// been modified since it was loaded along with the given token, on
+	// storages able to detect it.
+	StoreTag(ctx context.Context, tag string, members []byte, token any, expiration time.Duration) error
+	// DeleteTag removes the given tag
+	DeleteTag(ctx context.Context, tag string) error
+}
+
+// StoreTagIndex is a TagIndex storing the members of each tag as values of a
+// store. Members are length-prefixed, deduplicated and pruned once expired,
+// keys being indexed for at most the TTL of the index. Once a tag has
+// ChunkSize members, new ones are stored in overflow chunks of the same size,
+// so that adding a key only rewrites a bounded value.
+//
+// Updates of a tag are serialized locally, and retried when the storage
+// reports a concurrent modification by another client. A tag is popped by
+// replacing its value, so that keys added concurrently are not lost.
+type StoreTagIndex struct {
+	Storage TagIndexStorage
+	TTL     time.Duration
+	// ChunkSize is the number of keys stored in a single value,
+	// DefaultTagIndexChunkSize when zero
+	ChunkSize int
+
+	locks [tagIndexLocks]sync.Mutex
+}
+
+// NewTagIndex creates a new tag index storing the members of each tag in the
+// given storage, expiring after the given duration
+func NewTagIndex(storage TagIndexStorage, ttl time.Duration) *StoreTagIndex {
+	return &StoreTagIndex{
+		Storage: storage,
+		TTL:     ttl,
+	}
+}
+
+// Add associates the given key, expiring after the given duration (or never
+// when 0), with the given tags. The key is indexed for at most the TTL of the
+// index.
+func (i *StoreTagIndex) Add(ctx context.Context, key string, expiration time.Duration, tags []string) error {
+	if i.TTL > 0 && (expiration <= 0 || expiration > i.TTL) {
+		expiration = i.TTL
+	}
+
+	var expiresAt int64
+	if expiration > 0 {
+		expiresAt = time.Now().Add(expiration).UnixMilli()
+	}
+
+	errs := []error{}
+	added := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		if _, ok := added[tag]; ok {
+			continue
+		}
+		added[tag] = struct{}{}
+
+		if err := i.add(ctx, tag, key, expiresAt); err != nil {
+			errs = append(errs, fmt.Errorf("unable to add key %q to tag %q: %w", key, tag, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i *StoreTagIndex) add(ctx context.Context, tag string, key string, expiresAt int64) error {
+	mtx := i.lock(tag)
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	var err error
+
+	for attempt := 0; attempt < TagIndexMaxAttempts; attempt++ {
+		err = i.tryAdd(ctx, tag, key, expiresAt)
+		if !errors.Is(err, ErrTagIndexConflict) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// tryAdd adds the given key to the given tag, or to its last overflow chunk
+// once the tag is full
+func (i *StoreTagIndex) tryAdd(ctx context.Context, tag string, key string, expiresAt int64) error {
+	head, token, err := i.load(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	if !head.chunked() && (head.has(key) || len(head.keys) < i.chunkSize()) {
+		head.set(key, expiresAt)
+		return i.Storage.StoreTag(ctx, tag, head.encode(), token, i.TTL)
+	}
+
+	if !head.chunked() {
+		head.last = head.first
+	}
+
+	chunkTag := fmt.Sprintf(tagIndexChunkPattern, tag, head.last)
+	chunk, chunkToken, err := i.load(ctx, chunkTag)
+	if err != nil {
+		return err
+	}
+
+	if !chunk.has(key) && len(chunk.keys) >= i.chunkSize() {
+		head.last++
+
+		chunkTag = fmt.Sprintf(tagIndexChunkPattern, tag, head.last)
+		chunk, chunkToken, err = i.load(ctx, chunkTag)
+		if err != nil {
+			return err
+		}
+	}
+
+	chunk.set(key, expiresAt)
+
+	if err := i.Storage.StoreTag(ctx, chunkTag, chunk.encode(), chunkToken, i.TTL); err != nil {
+		return err
+	}
+
+	// The tag is written last, refreshing its TTL: when popped meanwhile, the
+	// key is added again to the emptied tag
+	return i.Storage.StoreTag(ctx, tag, head.encode(), token, i.TTL)
+}
+
+// Pop removes the given tag and returns the keys associated with it, except
+// the expired ones
+func (i *StoreTagIndex) Pop(ctx context.Context, tag string) ([]string, error) {
+	mtx := i.lock(tag)
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	var (
+		keys []string
+		err  error
+	)
+
+	for attempt := 0; attempt < TagIndexMaxAttempts; attempt++ {
+		keys, err = i.tryPop(ctx, tag)
+		if !errors.Is(err, ErrTagIndexConflict) {
+			return keys, err
+		}
+	}
+
+	return nil, err
+}
+
+// tryPop empties the given tag, replacing its value using the token it was
+// loaded with, then reads and removes its overflow chunks
+func (i *StoreTagIndex) tryPop(ctx context.Context, tag string) ([]string, error) {
+	value, token, err := i.Storage.LoadTag(ctx, tag)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	head, err := decodeTagMembers(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(head.keys) == 0 && !head.chunked() {
+		return nil, nil
+	}
+
+	// Next overflow chunks are numbered after the current ones, which are
+	// removed below
+	emptied := newTagMembers()
+	if head.chunked() {
+		emptied.first = head.last + 1
+		emptied.last = head.last
+	}
+
+	if err := i.Storage.StoreTag(ctx, tag, emptied.encode(), token, i.TTL); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	head.prune(now)
+	keys := head.keys
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		seen[key] = struct{}{}
+	}
+
+	errs := []error{}
+
+	for n := head.first; n <= head.last; n++ {
+		chunkTag := fmt.Sprintf(tagIndexChunkPattern, tag, n)
+
+		chunk, _, err := i.load(ctx, chunkTag)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, key := range chunk.keys {
+			if _, ok := seen[key]; !ok && !chunk.isExpired(key, now) {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+
+		if err := i.Storage.DeleteTag(ctx, chunkTag); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return keys, errors.Join(errs...)
+}
+
+// load returns the members of the given tag or chunk, without the expired
+// ones, along with the token they were loaded with
+func (i *StoreTagIndex) load(ctx context.Context, tag string) (*tagMembers, any, error) {
+	value, token, err := i.Storage.LoadTag(ctx, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := decodeTagMembers(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members.prune(time.Now())
+
+	return members, token, nil
+}
+
+// lock returns the lock serializing the updates of the given tag
+func (i *StoreTagIndex) lock(tag string) *sync.Mutex {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(tag))
+
+	return &i.locks[hash.Sum32()%tagIndexLocks]
+}
+
+func (i *StoreTagIndex) chunkSize() int {
+	if i.ChunkSize <= 0 {
+		return DefaultTagIndexChunkSize
+	}
+
+	return i.ChunkSize
+}
+
+// tagMembers are the keys associated with a tag along with their expiration
+// time in milliseconds (0 when they never expire), in insertion order, and the
+// range of the overflow chunks of the tag (empty when first > last)
+type tagMembers struct {
+	keys      []string
+	expiresAt map[string]int64
+	first     int64
+	last      int64
+}
+
+func newTagMembers() *tagMembers {
+	return &tagMembers{
+		expiresAt: map[string]int64{},
+		first:     1,
+	}
+}
+
+// chunked returns whether the tag has overflow chunks
+func (m *tagMembers) chunked() bool {
+	return m.first <= m.last
+}
+
+func (m *tagMembers) has(key string) bool {
+	_, ok := m.expiresAt[key]
+	return ok
+}
+
+func (m *tagMembers) isExpired(key string, now time.Time) bool {
+	expiresAt := m.expiresAt[key]
+	return expiresAt != 0 && expiresAt <= now.UnixMilli()
+}
+
+func (m *tagMembers) set(key string, expiresAt int64) {
+	if _, ok := m.expiresAt[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.expiresAt[key] = expiresAt
+}
+
+func (m *tagMembers) prune(now time.Time) {
+	keys := m.keys[:0]
+	for _, key := range m.keys {
+		if m.isExpired(key, now) {
+			delete(m.expiresAt, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	m.keys = keys
+}
+
+// encode returns the members as the header (followed by the range of the
+// overflow chunks, when the tag has or had some) followed by, for each key, its length, the key
+// itself and its expiration time, as varints
+func (m *tagMembers) encode() []byte {
+	value := []byte(tagIndexHeader)
+	if m.first != 1 || m.last != 0 {
+		value = []byte(tagIndexChunkedHeader)
+		value = binary.AppendVarint(value, m.first)
+		value = binary.AppendVarint(value, m.last)
+	}
+
+	for _, key := range m.keys {
+		value = binary.AppendUvarint(value, uint64(len(key)))
+		value = append(value, key...)
+		value = binary.AppendVarint(value, m.expiresAt[key])
+	}
+
+	return value
+}
+
+// decodeTagMembers decodes the members of a tag, either encoded or written as
+// comma-joined keys by previous versions
+func decodeTagMembers(value []byte) (*tagMembers, error) {
+	members := newTagMembers()
+
+	switch {
+	case bytes.HasPrefix(value, []byte(tagIndexHeader)):
+		value = value[len(tagIndexHeader):]
+
+	case bytes.HasPrefix(value, []byte(tagIndexChunkedHeader)):
+		value = value[len(tagIndexChunkedHeader):]
+
+		var n int
+		if members.first, n = binary.Varint(value); n <= 0 {
+			return nil, ErrInvalidTagIndex
+		}
+		value = value[n:]
+		if members.last, n = binary.Varint(value); n <= 0 {
+			return nil, ErrInvalidTagIndex
+		}
+		value = value[n:]
+
+	default:
+		for _, key := range strings.Split(string(value), ",") {
+			if key != "" {
+				members.set(key, 0)
+			}
+		}
+		return members, nil
+	}
+
+	for len(value) > 0 {
+		length, n := binary.Uvarint(value)
+		if n <= 0 || uint64(len(value)-n) < length {
+			return nil, ErrInvalidTagIndex
+		}
+		key := string(value[n : n+int(length)])
+		value = value[n+int(length):]
+
+		expiresAt, n := binary.Varint(value)
+		if n <= 0 {
+			return nil, ErrInvalidTagIndex
+		}
+		value = value[n:]
+
+		members.set(key, expiresAt)
+	}
+
+	return members, nil
+}
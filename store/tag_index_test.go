@@ -0,0 +1,503 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prodadidb/gocache/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/exp/slices"
+)
+
+// tagMembers returns the given keys, never expiring, encoded as stored by a
+// StoreTagIndex
+func tagMembers(keys ...string) []byte {
+	value := []byte("\x00gct\x01")
+	for _, key := range keys {
+		value = binary.AppendUvarint(value, uint64(len(key)))
+		value = append(value, key...)
+		value = binary.AppendVarint(value, 0)
+	}
+	return value
+}
+
+// tagMemberKeys returns the keys of the given members of a tag, encoded as
+// stored by a StoreTagIndex, along with whether they expire
+func tagMemberKeys(t *testing.T, value []byte) map[string]bool {
+	t.Helper()
+
+	keys := map[string]bool{}
+
+	assert.True(t, bytes.HasPrefix(value, []byte("\x00gct\x01")))
+	value = value[len("\x00gct\x01"):]
+
+	for len(value) > 0 {
+		length, n := binary.Uvarint(value)
+		key := string(value[n : n+int(length)])
+		value = value[n+int(length):]
+
+		expiresAt, n := binary.Varint(value)
+		value = value[n:]
+
+		keys[key] = expiresAt != 0
+	}
+
+	return keys
+}
+
+// tagMemberList returns the keys of the given members of a tag, encoded as
+// stored by a StoreTagIndex, in order
+func tagMemberList(t *testing.T, value []byte) []string {
+	t.Helper()
+
+	keys, ok := decodeTagMemberList(value)
+	assert.True(t, ok)
+
+	return keys
+}
+
+func decodeTagMemberList(value []byte) ([]string, bool) {
+	switch {
+	case bytes.HasPrefix(value, []byte("\x00gct\x01")):
+		value = value[len("\x00gct\x01"):]
+	case bytes.HasPrefix(value, []byte("\x00gct\x02")):
+		value = value[len("\x00gct\x02"):]
+		for i := 0; i < 2; i++ {
+			_, n := binary.Varint(value)
+			if n <= 0 {
+				return nil, false
+			}
+			value = value[n:]
+		}
+	default:
+		return nil, false
+	}
+
+	keys := []string{}
+	for len(value) > 0 {
+		length, n := binary.Uvarint(value)
+		if n <= 0 || uint64(len(value)-n) < length {
+			return nil, false
+		}
+		keys = append(keys, string(value[n:n+int(length)]))
+		value = value[n+int(length):]
+
+		_, n = binary.Varint(value)
+		if n <= 0 {
+			return nil, false
+		}
+		value = value[n:]
+	}
+
+	return keys, true
+}
+
+// tagMembersMatcher matches the members of a tag, encoded as stored by a
+// StoreTagIndex, having the given keys whatever their expiration
+type tagMembersMatcher struct {
+	keys []string
+}
+
+func matchTagMembers(keys ...string) gomock.Matcher {
+	return tagMembersMatcher{keys: keys}
+}
+
+func (m tagMembersMatcher) Matches(x any) bool {
+	value, ok := x.([]byte)
+	if !ok {
+		return false
+	}
+
+	keys, ok := decodeTagMemberList(value)
+
+	return ok && slices.Equal(keys, m.keys)
+}
+
+func (m tagMembersMatcher) String() string {
+	return fmt.Sprintf("tag members %v", m.keys)
+}
+
+// fakeTagStorage is a TagIndexStorage keeping tags in memory, detecting
+// concurrent modifications using a version per tag
+type fakeTagStorage struct {
+	mtx       sync.Mutex
+	values    map[string][]byte
+	versions  map[string]int
+	conflicts int
+	err       error
+}
+
+func newFakeTagStorage() *fakeTagStorage {
+	return &fakeTagStorage{
+		values:   map[string][]byte{},
+		versions: map[string]int{},
+	}
+}
+
+func (s *fakeTagStorage) LoadTag(_ context.Context, tag string) ([]byte, any, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+
+	return s.values[tag], s.versions[tag], nil
+}
+
+func (s *fakeTagStorage) StoreTag(_ context.Context, tag string, members []byte, token any, _ time.Duration) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.conflicts > 0 {
+		// Simulates a modification by another client
+		s.conflicts--
+		s.versions[tag]++
+	}
+	if token != s.versions[tag] {
+		return store.ErrTagIndexConflict
+	}
+
+	s.values[tag] = members
+	s.versions[tag]++
+
+	return nil
+}
+
+func (s *fakeTagStorage) DeleteTag(_ context.Context, tag string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.values, tag)
+	s.versions[tag]++
+
+	return nil
+}
+
+func TestNewTagIndex(t *testing.T) {
+	// Given
+	storage := newFakeTagStorage()
+
+	// When
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// Then
+	assert.Equal(t, storage, index.Storage)
+	assert.Equal(t, time.Hour, index.TTL)
+}
+
+func TestTagIndexAdd(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err1 := index.Add(ctx, "key-1", 0, []string{"tag1", "tag2", "tag1"})
+	err2 := index.Add(ctx, "key,2", 0, []string{"tag1"})
+	err3 := index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+
+	assert.Equal(t, []string{"key-1", "key,2"}, tagMemberList(t, storage.values["tag1"]))
+	assert.Equal(t, []string{"key-1"}, tagMemberList(t, storage.values["tag2"]))
+}
+
+func TestTagIndexAddWhenLegacyMembers(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	storage.values["tag1"] = []byte("key-1,key-2")
+
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err := index.Add(ctx, "key-2", 0, []string{"tag1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1", "key-2"}, tagMemberList(t, storage.values["tag1"]))
+}
+
+func TestTagIndexAddWhenConflict(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	storage.conflicts = 2
+
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err := index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1"}, tagMemberList(t, storage.values["tag1"]))
+}
+
+func TestTagIndexAddWhenConflictPersists(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	storage.conflicts = store.TagIndexMaxAttempts
+
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err := index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	// Then
+	assert.True(t, errors.Is(err, store.ErrTagIndexConflict))
+	assert.Nil(t, storage.values["tag1"])
+}
+
+func TestTagIndexAddWhenInvalidMembers(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	storage.values["tag1"] = []byte("\x00gct\x01\x10key")
+
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err := index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	// Then
+	assert.True(t, errors.Is(err, store.ErrInvalidTagIndex))
+}
+
+func TestTagIndexAddWhenError(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	expectedErr := errors.New("unable to load tag")
+
+	storage := newFakeTagStorage()
+	storage.err = expectedErr
+
+	index := store.NewTagIndex(storage, time.Hour)
+
+	// When
+	err := index.Add(ctx, "key-1", 0, []string{"tag1", "tag2"})
+
+	// Then
+	assert.True(t, errors.Is(err, expectedErr))
+	assert.Contains(t, err.Error(), `unable to add key "key-1" to tag "tag1"`)
+	assert.Contains(t, err.Error(), `unable to add key "key-1" to tag "tag2"`)
+}
+
+func TestTagIndexPop(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	index := store.NewTagIndex(storage, time.Hour)
+
+	_ = index.Add(ctx, "key-1", 0, []string{"tag1"})
+	_ = index.Add(ctx, "key-2", time.Hour, []string{"tag1", "tag2"})
+
+	// When
+	keys, err := index.Pop(ctx, "tag1")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1", "key-2"}, keys)
+
+	assert.Equal(t, tagMembers(), storage.values["tag1"])
+	assert.Equal(t, []string{"key-2"}, tagMemberList(t, storage.values["tag2"]))
+}
+
+func TestTagIndexPopWhenMissing(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	index := store.NewTagIndex(newFakeTagStorage(), time.Hour)
+
+	// When
+	keys, err := index.Pop(ctx, "tag1")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestTagIndexPruneExpiredMembers(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	index := store.NewTagIndex(storage, time.Hour)
+
+	_ = index.Add(ctx, "key-1", 10*time.Millisecond, []string{"tag1"})
+	_ = index.Add(ctx, "key-2", time.Hour, []string{"tag1"})
+
+	time.Sleep(20 * time.Millisecond)
+
+	// When
+	err := index.Add(ctx, "key-3", 0, []string{"tag1"})
+
+	// Then
+	assert.Nil(t, err)
+
+	keys, err := index.Pop(ctx, "tag1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-2", "key-3"}, keys)
+}
+
+func TestTagIndexRefreshExpiration(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	index := store.NewTagIndex(newFakeTagStorage(), time.Hour)
+
+	_ = index.Add(ctx, "key-1", 10*time.Millisecond, []string{"tag1"})
+	_ = index.Add(ctx, "key-1", time.Hour, []string{"tag1"})
+
+	time.Sleep(20 * time.Millisecond)
+
+	// When
+	keys, err := index.Pop(ctx, "tag1")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1"}, keys)
+}
+
+func TestTagIndexAddConcurrently(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	index := store.NewTagIndex(newFakeTagStorage(), time.Hour)
+
+	// When
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = index.Add(ctx, string(rune('a'+i)), 0, []string{"tag1"})
+		}(i)
+	}
+	wg.Wait()
+
+	// Then
+	keys, err := index.Pop(ctx, "tag1")
+	assert.Nil(t, err)
+	assert.Len(t, keys, 50)
+}
+
+func TestTagIndexPopWhenConflict(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+	index := store.NewTagIndex(storage, time.Hour)
+
+	_ = index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	storage.conflicts = 1
+
+	// When
+	keys, err := index.Pop(ctx, "tag1")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1"}, keys)
+	assert.Equal(t, tagMembers(), storage.values["tag1"])
+}
+
+func TestTagIndexAddWhenChunked(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+
+	index := store.NewTagIndex(storage, time.Hour)
+	index.ChunkSize = 2
+
+	// When
+	for _, key := range []string{"key-1", "key-2", "key-3", "key-4", "key-5", "key-3"} {
+		err := index.Add(ctx, key, 0, []string{"tag1"})
+		assert.Nil(t, err)
+	}
+
+	// Then
+	assert.Equal(t, []string{"key-1", "key-2"}, tagMemberList(t, storage.values["tag1"]))
+	assert.Equal(t, []string{"key-3", "key-4"}, tagMemberList(t, storage.values["tag1:gocache_chunk_1"]))
+	// Keys set again are added to the last chunk, and deduplicated when popped
+	assert.Equal(t, []string{"key-5", "key-3"}, tagMemberList(t, storage.values["tag1:gocache_chunk_2"]))
+
+	keys, err := index.Pop(ctx, "tag1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-1", "key-2", "key-3", "key-4", "key-5"}, keys)
+
+	assert.Empty(t, tagMemberList(t, storage.values["tag1"]))
+	assert.NotContains(t, storage.values, "tag1:gocache_chunk_1")
+	assert.NotContains(t, storage.values, "tag1:gocache_chunk_2")
+}
+
+func TestTagIndexAddWhenChunkedAndPopped(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	storage := newFakeTagStorage()
+
+	index := store.NewTagIndex(storage, time.Hour)
+	index.ChunkSize = 1
+
+	_ = index.Add(ctx, "key-1", 0, []string{"tag1"})
+	_ = index.Add(ctx, "key-2", 0, []string{"tag1"})
+	_, _ = index.Pop(ctx, "tag1")
+
+	// When
+	err1 := index.Add(ctx, "key-3", 0, []string{"tag1"})
+	err2 := index.Add(ctx, "key-4", 0, []string{"tag1"})
+
+	// Then
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+
+	// Overflow chunks removed by the previous pop are not reused
+	assert.Equal(t, []string{"key-3"}, tagMemberList(t, storage.values["tag1"]))
+	assert.NotContains(t, storage.values, "tag1:gocache_chunk_1")
+	assert.Equal(t, []string{"key-4"}, tagMemberList(t, storage.values["tag1:gocache_chunk_2"]))
+
+	keys, err := index.Pop(ctx, "tag1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-3", "key-4"}, keys)
+}
+
+func TestTagIndexPruneMembersWithoutExpiration(t *testing.T) {
+	// Given
+	ctx := context.Background()
+
+	index := store.NewTagIndex(newFakeTagStorage(), 10*time.Millisecond)
+
+	_ = index.Add(ctx, "key-1", 0, []string{"tag1"})
+
+	time.Sleep(20 * time.Millisecond)
+
+	_ = index.Add(ctx, "key-2", 0, []string{"tag1"})
+
+	// When
+	keys, err := index.Pop(ctx, "tag1")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key-2"}, keys)
+}